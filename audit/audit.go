@@ -0,0 +1,532 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file audit.go
+ * @package audit
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+	"github.com/zenkoo-live/svc.base/defs"
+	"github.com/zenkoo-live/svc.base/utils"
+	"go-micro.dev/v4/broker"
+)
+
+const (
+	DefaultTable = "audit_logs"
+	DefaultTopic = "audit"
+
+	ActionInsert = "insert"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Auditable models are recorded by Hook, AuditSkip lists columns kept out
+// of records, like password or token
+type Auditable interface {
+	AuditSkip() []string
+}
+
+// Record is one changed row. Before and After hold changed columns on
+// update, all columns on insert and delete. Inserted and updated values are
+// read back from database, so columns not written by the query are left
+// out.
+type Record struct {
+	bun.BaseModel `bun:"table:audit_logs"`
+
+	ID         int64          `bun:"id,pk,autoincrement" json:"-"`
+	Table      string         `bun:"table_name,notnull" json:"table"`
+	PrimaryKey string         `bun:"primary_key,notnull" json:"primary_key"`
+	Action     string         `bun:"action,notnull" json:"action"`
+	Before     map[string]any `bun:"before" json:"before,omitempty"`
+	After      map[string]any `bun:"after" json:"after,omitempty"`
+	Actor      uuid.UUID      `bun:"actor,nullzero" json:"actor,omitempty"`
+	RequestID  string         `bun:"request_id" json:"request_id,omitempty"`
+	CreatedAt  time.Time      `bun:"created_at,notnull" json:"created_at"`
+}
+
+// Sink stores records, conn is connection of the changing query
+type Sink func(ctx context.Context, db *bun.DB, conn bun.IConn, records []*Record) error
+
+// DatabaseSink inserts records with connection of the changing query, which
+// is the same transaction if the query runs in one
+func DatabaseSink(table string) Sink {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	return func(ctx context.Context, db *bun.DB, conn bun.IConn, records []*Record) error {
+		_, err := db.NewInsert().Conn(conn).Model(&records).ModelTableExpr(table).Exec(ctx)
+
+		return err
+	}
+}
+
+// BrokerSink publishes records as JSON to topic. Records of a transaction
+// are held in context of Defer and published by Flush after commit, so
+// rolled back changes are never published. Transactions without Defer are
+// refused.
+func BrokerSink(brk func() broker.Broker, topic string) Sink {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+
+	return func(ctx context.Context, db *bun.DB, conn bun.IConn, records []*Record) error {
+		publish := func() error {
+			return publishRecords(brk(), topic, records)
+		}
+
+		switch conn.(type) {
+		case *sql.Tx, bun.Tx:
+			d, ok := ctx.Value(deferredKey{}).(*deferred)
+			if !ok {
+				return fmt.Errorf("audit: transaction without Defer, records for %s dropped", topic)
+			}
+
+			d.add(publish)
+
+			return nil
+		default:
+			// Query committed already
+			return publish()
+		}
+	}
+}
+
+func publishRecords(b broker.Broker, topic string, records []*Record) error {
+	if b == nil {
+		return fmt.Errorf("audit: no broker for topic %s", topic)
+	}
+
+	for _, rec := range records {
+		body, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		err = b.Publish(topic, &broker.Message{
+			Header: map[string]string{"table": rec.Table, "action": rec.Action},
+			Body:   body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateTable creates audit table if not exists
+func CreateTable(ctx context.Context, db bun.IDB, table string) error {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	_, err := db.NewCreateTable().Model((*Record)(nil)).ModelTableExpr(table).IfNotExists().Exec(ctx)
+
+	return err
+}
+
+type (
+	beforeKey   struct{}
+	errorsKey   struct{}
+	deferredKey struct{}
+)
+
+// deferred holds publishing of a transaction until it commits, parent is the
+// enclosing transaction of a savepoint
+type deferred struct {
+	mu      sync.Mutex
+	parent  *deferred
+	publish []func() error
+}
+
+func (d *deferred) add(fn ...func() error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.publish = append(d.publish, fn...)
+}
+
+// Defer returns ctx for a transaction, BrokerSink holds records of it until
+// Flush. Under ctx of another Defer, records go to the enclosing transaction
+// on Flush.
+func Defer(ctx context.Context) context.Context {
+	d := &deferred{}
+	d.parent, _ = ctx.Value(deferredKey{}).(*deferred)
+
+	return context.WithValue(ctx, deferredKey{}, d)
+}
+
+// Flush publishes records held since Defer, call it after the transaction
+// commits. Records of a savepoint are handed to the enclosing transaction.
+// Dropping ctx without Flush discards them, as on rollback.
+func Flush(ctx context.Context) error {
+	d, ok := ctx.Value(deferredKey{}).(*deferred)
+	if !ok {
+		return nil
+	}
+
+	d.mu.Lock()
+	publish := d.publish
+	d.publish = nil
+	d.mu.Unlock()
+
+	if d.parent != nil {
+		d.parent.add(publish...)
+
+		return nil
+	}
+
+	var errs error
+	for _, fn := range publish {
+		errs = errors.Join(errs, fn())
+	}
+
+	return errs
+}
+
+// failures collects errors of strict Hook in context
+type failures struct {
+	mu  sync.Mutex
+	err error
+}
+
+// WithErrors returns ctx collecting failures of strict Hook, see Errors
+func WithErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, errorsKey{}, &failures{})
+}
+
+// Errors returns failures of strict Hook in queries under ctx of WithErrors
+func Errors(ctx context.Context) error {
+	f, ok := ctx.Value(errorsKey{}).(*failures)
+	if !ok {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.err
+}
+
+// query is implemented by bun insert, update and delete queries
+type query interface {
+	bun.Query
+	DB() *bun.DB
+	GetConn() bun.IConn
+}
+
+// Hook records inserts, updates and deletes of Auditable models. Only
+// queries with model values are recorded, bulk queries built by Set and
+// Where have no rows to compare.
+//
+// Hooks of bun could not fail the query, so failures go to onError and the
+// query stands. A strict hook also collects them in context of WithErrors,
+// for the caller to roll back.
+type Hook struct {
+	sink    Sink
+	onError func(error)
+	strict  bool
+}
+
+type HookOption func(*Hook)
+
+// Strict collects failures in context of WithErrors besides onError
+func Strict() HookOption {
+	return func(h *Hook) {
+		h.strict = true
+	}
+}
+
+func NewHook(sink Sink, onError func(error), opts ...HookOption) *Hook {
+	h := &Hook{
+		sink:    sink,
+		onError: onError,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// BeforeQuery loads current rows of updated or deleted models
+func (h *Hook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	var q query
+	switch tq := event.IQuery.(type) {
+	case *bun.UpdateQuery:
+		q = tq
+	case *bun.DeleteQuery:
+		q = tq
+	default:
+		return ctx
+	}
+
+	table, rows := auditable(q)
+	if len(rows) == 0 {
+		return ctx
+	}
+
+	before := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		cur, err := load(ctx, q, table, row)
+		if err != nil {
+			h.fail(ctx, fmt.Errorf("audit: load %s : %w", table.Name, err))
+
+			continue
+		}
+
+		before[i] = columns(table, cur, row.Interface().(Auditable).AuditSkip())
+	}
+
+	return context.WithValue(ctx, beforeKey{}, before)
+}
+
+func (h *Hook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if event.Err != nil {
+		return
+	}
+
+	var (
+		q      query
+		action string
+	)
+	switch tq := event.IQuery.(type) {
+	case *bun.InsertQuery:
+		q, action = tq, ActionInsert
+	case *bun.UpdateQuery:
+		q, action = tq, ActionUpdate
+	case *bun.DeleteQuery:
+		q, action = tq, ActionDelete
+	default:
+		return
+	}
+
+	table, rows := auditable(q)
+	if len(rows) == 0 {
+		return
+	}
+
+	before, _ := ctx.Value(beforeKey{}).([]map[string]any)
+	actor, _ := defs.ActorFromContext(ctx)
+	rid := utils.RequestIDFromContext(ctx)
+	now := time.Now()
+
+	var records []*Record
+	for i, row := range rows {
+		rec := &Record{
+			Table:      table.Name,
+			PrimaryKey: primaryKey(table, row),
+			Action:     action,
+			Actor:      actor,
+			RequestID:  rid,
+			CreatedAt:  now,
+		}
+
+		skip := row.Interface().(Auditable).AuditSkip()
+		switch action {
+		case ActionInsert, ActionUpdate:
+			if action == ActionUpdate && (i >= len(before) || before[i] == nil) {
+				continue
+			}
+
+			// Model holds columns the query did not write, like those
+			// outside of Column or zero with OmitZero
+			cur, err := load(ctx, q, table, row)
+			if err != nil {
+				h.fail(ctx, fmt.Errorf("audit: load %s : %w", table.Name, err))
+
+				continue
+			}
+
+			rec.After = columns(table, cur, skip)
+			if action == ActionUpdate {
+				rec.Before, rec.After = diff(before[i], rec.After)
+				if len(rec.After) == 0 {
+					continue
+				}
+			}
+		case ActionDelete:
+			if i < len(before) && before[i] != nil {
+				rec.Before = before[i]
+			} else {
+				rec.Before = columns(table, row, skip)
+			}
+		}
+
+		records = append(records, rec)
+	}
+
+	if len(records) == 0 {
+		return
+	}
+
+	err := h.sink(ctx, q.DB(), q.GetConn(), records)
+	if err != nil {
+		h.fail(ctx, fmt.Errorf("audit: record %s : %w", table.Name, err))
+	}
+}
+
+func (h *Hook) fail(ctx context.Context, err error) {
+	if h.onError != nil {
+		h.onError(err)
+	}
+
+	if !h.strict {
+		return
+	}
+
+	if f, ok := ctx.Value(errorsKey{}).(*failures); ok {
+		f.mu.Lock()
+		f.err = errors.Join(f.err, err)
+		f.mu.Unlock()
+	}
+}
+
+// load reads current row of model by primary key, with connection of q
+func load(ctx context.Context, q query, table *schema.Table, row reflect.Value) (reflect.Value, error) {
+	cur := reflect.New(table.Type)
+	for _, pk := range table.PKs {
+		pk.Value(cur.Elem()).Set(pk.Value(row.Elem()))
+	}
+
+	sq := q.DB().NewSelect().Conn(q.GetConn()).Model(cur.Interface()).WherePK()
+	if table.SoftDeleteField != nil {
+		sq.WhereAllWithDeleted()
+	}
+
+	return cur, sq.Scan(ctx)
+}
+
+// auditable returns struct pointers in model values of q, if model is
+// Auditable
+func auditable(q query) (*schema.Table, []reflect.Value) {
+	model := q.GetModel()
+	if model == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(model.Value())
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return nil, nil
+	}
+
+	var rows []reflect.Value
+	switch ev := reflect.Indirect(v); ev.Kind() {
+	case reflect.Struct:
+		rows = append(rows, ev.Addr())
+	case reflect.Slice:
+		for i := 0; i < ev.Len(); i++ {
+			elem := ev.Index(i)
+			if elem.Kind() != reflect.Pointer {
+				elem = elem.Addr()
+			}
+
+			if !elem.IsNil() {
+				rows = append(rows, elem)
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := rows[0].Interface().(Auditable); !ok {
+		return nil, nil
+	}
+
+	return q.DB().Table(rows[0].Type().Elem()), rows
+}
+
+func columns(table *schema.Table, row reflect.Value, skip []string) map[string]any {
+	ret := make(map[string]any, len(table.Fields))
+	for _, f := range table.Fields {
+		skipped := false
+		for _, s := range skip {
+			if strings.EqualFold(s, f.Name) {
+				skipped = true
+
+				break
+			}
+		}
+
+		if !skipped {
+			ret[f.Name] = f.Value(row.Elem()).Interface()
+		}
+	}
+
+	return ret
+}
+
+func primaryKey(table *schema.Table, row reflect.Value) string {
+	keys := make([]string, 0, len(table.PKs))
+	for _, pk := range table.PKs {
+		keys = append(keys, fmt.Sprint(pk.Value(row.Elem()).Interface()))
+	}
+
+	return strings.Join(keys, ",")
+}
+
+// diff keeps columns with different values
+func diff(before, after map[string]any) (map[string]any, map[string]any) {
+	b := make(map[string]any)
+	a := make(map[string]any)
+	for k, av := range after {
+		bv := before[k]
+		if !equal(bv, av) {
+			b[k] = bv
+			a[k] = av
+		}
+	}
+
+	return b, a
+}
+
+func equal(x, y any) bool {
+	// Databases keep times in lower precision and other locations
+	if tx, ok := x.(time.Time); ok {
+		if ty, ok := y.(time.Time); ok {
+			d := tx.Sub(ty)
+
+			return d > -time.Second && d < time.Second
+		}
+	}
+
+	xj, _ := json.Marshal(x)
+	yj, _ := json.Marshal(y)
+
+	return bytes.Equal(xj, yj)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
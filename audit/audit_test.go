@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file audit_test.go
+ * @package audit
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/internal/testdb"
+)
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users"`
+
+	ID        int64 `bun:",pk,autoincrement"`
+	Name      string
+	Email     string
+	Password  string
+	CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+}
+
+func (*testUser) AuditSkip() []string {
+	return []string{"password"}
+}
+
+func testDB(t *testing.T, sink Sink, opts ...HookOption) (*bun.DB, *[]error) {
+	t.Helper()
+
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	_, err := db.NewCreateTable().Model((*testUser)(nil)).Exec(ctx)
+	if err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	err = CreateTable(ctx, db, "")
+	if err != nil {
+		t.Fatalf("create audit table: %s", err)
+	}
+
+	var failed []error
+	db.AddQueryHook(NewHook(sink, func(err error) {
+		failed = append(failed, err)
+	}, opts...))
+
+	return db, &failed
+}
+
+func records(t *testing.T, db *bun.DB) []*Record {
+	t.Helper()
+
+	var recs []*Record
+	err := db.NewSelect().Model(&recs).Order("id").Scan(context.Background())
+	if err != nil {
+		t.Fatalf("load records: %s", err)
+	}
+
+	return recs
+}
+
+func TestHook(t *testing.T) {
+	db, failed := testDB(t, DatabaseSink(""))
+	ctx := context.Background()
+
+	u := &testUser{Name: "bob", Email: "bob@a", Password: "secret"}
+	_, err := db.NewInsert().Model(u).Exec(ctx)
+	if err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	// Partial update, model holds other values than database
+	_, err = db.NewUpdate().Model(&testUser{ID: u.ID, Name: "alice"}).Column("name").WherePK().Exec(ctx)
+	if err != nil {
+		t.Fatalf("update name: %s", err)
+	}
+
+	// Nothing changed
+	_, err = db.NewUpdate().Model(&testUser{ID: u.ID, Name: "alice"}).Column("name").WherePK().Exec(ctx)
+	if err != nil {
+		t.Fatalf("update same name: %s", err)
+	}
+
+	_, err = db.NewUpdate().Model(&testUser{ID: u.ID, Email: "alice@a"}).OmitZero().WherePK().Exec(ctx)
+	if err != nil {
+		t.Fatalf("update email: %s", err)
+	}
+
+	_, err = db.NewDelete().Model(&testUser{ID: u.ID}).WherePK().Exec(ctx)
+	if err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+
+	if len(*failed) > 0 {
+		t.Fatalf("hook failed: %v", *failed)
+	}
+
+	recs := records(t, db)
+	if len(recs) != 4 {
+		t.Fatalf("%d records, want 4", len(recs))
+	}
+
+	cases := []struct {
+		action string
+		before []string
+		after  []string
+	}{
+		{ActionInsert, nil, []string{"id", "name", "email", "created_at"}},
+		{ActionUpdate, []string{"name"}, []string{"name"}},
+		{ActionUpdate, []string{"email"}, []string{"email"}},
+		{ActionDelete, []string{"id", "name", "email", "created_at"}, nil},
+	}
+
+	for i, c := range cases {
+		rec := recs[i]
+		if rec.Action != c.action || rec.Table != "users" || rec.PrimaryKey == "" {
+			t.Errorf("record %d: %s %s %s", i, rec.Action, rec.Table, rec.PrimaryKey)
+		}
+
+		if !sameKeys(rec.Before, c.before) || !sameKeys(rec.After, c.after) {
+			t.Errorf("record %d %s: before %v, after %v, want keys %v, %v", i, rec.Action, rec.Before, rec.After, c.before, c.after)
+		}
+	}
+
+	if recs[1].Before["name"] != "bob" || recs[1].After["name"] != "alice" {
+		t.Errorf("name change recorded as %v -> %v", recs[1].Before, recs[1].After)
+	}
+}
+
+func sameKeys(m map[string]any, keys []string) bool {
+	if len(m) != len(keys) {
+		return false
+	}
+
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestHookStrict(t *testing.T) {
+	boom := errors.New("boom")
+	failing := func(ctx context.Context, db *bun.DB, conn bun.IConn, records []*Record) error {
+		return boom
+	}
+
+	for _, strict := range []bool{false, true} {
+		var opts []HookOption
+		if strict {
+			opts = append(opts, Strict())
+		}
+
+		db, failed := testDB(t, failing, opts...)
+		ctx := WithErrors(context.Background())
+		_, err := db.NewInsert().Model(&testUser{Name: "bob"}).Exec(ctx)
+		if err != nil {
+			t.Fatalf("insert: %s", err)
+		}
+
+		if len(*failed) != 1 {
+			t.Errorf("strict %v: %d failures reported", strict, len(*failed))
+		}
+
+		if err := Errors(ctx); errors.Is(err, boom) != strict {
+			t.Errorf("strict %v: collected %v", strict, err)
+		}
+	}
+
+	if Errors(context.Background()) != nil {
+		t.Error("errors without WithErrors")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	before := map[string]any{"a": 1, "b": "x", "t": now, "n": nil}
+	after := map[string]any{"a": 1, "b": "y", "t": now.Add(time.Millisecond).UTC(), "n": nil}
+
+	b, a := diff(before, after)
+	if len(b) != 1 || len(a) != 1 || b["b"] != "x" || a["b"] != "y" {
+		t.Errorf("diff = %v, %v", b, a)
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,43 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file context.go
+ * @package defs
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package defs
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type actorKey struct{}
+
+// WithActor returns ctx carrying ID of the user who makes changes, set by
+// session middleware
+func WithActor(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey{}, id)
+}
+
+func ActorFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(actorKey{}).(uuid.UUID)
+
+	return id, ok && id != uuid.Nil
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -20,6 +20,7 @@ type RuntimePlatform int32
 const (
 	SaltLength      = 16
 	DefaultPageSize = 10
+	MaxPageSize     = 100
 
 	TokenIssuer = "zenkoo-live"
 
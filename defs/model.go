@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file model.go
+ * @package defs
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package defs
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var _ bun.BeforeAppendModelHook = (*Entity)(nil)
+
+// Entity is the common columns of models, deletes are soft. ID,
+// timestamps and actors are filled on insert and update, actor comes from
+// WithActor.
+type Entity struct {
+	ID        uuid.UUID `bun:"id,pk" json:"id"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp" json:"updated_at"`
+	DeletedAt time.Time `bun:"deleted_at,soft_delete,nullzero" json:"deleted_at,omitempty"`
+	CreatedBy uuid.UUID `bun:"created_by,nullzero" json:"created_by"`
+	UpdatedBy uuid.UUID `bun:"updated_by,nullzero" json:"updated_by"`
+}
+
+// BeforeAppendModel fills columns of inserted or updated model. Updates with
+// Column or Set write updated_at only if listed, see Touch.
+func (m *Entity) BeforeAppendModel(ctx context.Context, q bun.Query) error {
+	now := time.Now()
+	actor, _ := ActorFromContext(ctx)
+	switch q.(type) {
+	case *bun.InsertQuery:
+		if m.ID == uuid.Nil {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+
+			m.ID = id
+		}
+
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = now
+		}
+
+		if m.CreatedBy == uuid.Nil {
+			m.CreatedBy = actor
+		}
+
+		m.UpdatedAt = m.CreatedAt
+		m.UpdatedBy = m.CreatedBy
+	case *bun.UpdateQuery:
+		m.UpdatedAt = now
+		if actor != uuid.Nil {
+			m.UpdatedBy = actor
+		}
+	}
+
+	return nil
+}
+
+// Touch sets updated_at and updated_by on bulk or partial updates
+func Touch(ctx context.Context, q *bun.UpdateQuery) *bun.UpdateQuery {
+	q.Set("updated_at = ?", time.Now())
+	if actor, ok := ActorFromContext(ctx); ok {
+		q.Set("updated_by = ?", actor)
+	}
+
+	return q
+}
+
+// Restore undeletes soft deleted model by primary key
+func Restore(ctx context.Context, db bun.IDB, model any) error {
+	q := db.NewUpdate().Model(model).WherePK().WhereDeleted().Set("deleted_at = NULL")
+	_, err := Touch(ctx, q).Exec(ctx)
+
+	return err
+}
+
+// PurgeDeleted removes rows of model soft deleted before t, returns number
+// of removed rows
+func PurgeDeleted(ctx context.Context, db bun.IDB, model any, before time.Time) (int64, error) {
+	res, err := db.NewDelete().
+		Model(model).
+		WhereDeleted().
+		Where("?TableAlias.deleted_at < ?", before).
+		ForceDelete().
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file testdb.go
+ * @package testdb
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package testdb
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+)
+
+// SQLite opens a sqlite database file in temp dir of t, closed on cleanup.
+// Concurrent connections wait for locks instead of failing.
+func SQLite(t testing.TB) *bun.DB {
+	t.Helper()
+
+	sqldb, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("open sqlite: %s", err)
+	}
+
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
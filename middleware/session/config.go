@@ -33,12 +33,16 @@ type Config struct {
 	DataKey string
 	// Expiration duration
 	Expiration time.Duration
+	// Optional, changes expiration of running middleware, see NewExpiration
+	ExpirationHandle *Expiration
 	// Auto refresh expiry
 	AutoRefreshExpiration bool
 	// Strict session data
 	StrictAuth string
-	// Storage, Redis client
-	Storage *redis.Client
+	// Session data key of user ID, put into user context by defs.WithActor
+	ActorKey string
+	// Storage, Redis client, cluster or failover client
+	Storage redis.UniversalClient
 }
 
 var ConfigDefault = Config{
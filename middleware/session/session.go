@@ -16,11 +16,13 @@ package session
 import (
 	"errors"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/zenkoo-live/svc.base/defs"
 	"github.com/zenkoo-live/svc.base/utils"
 )
 
@@ -28,15 +30,43 @@ var (
 	IDKey    string
 	IDSource string
 	DataKey  string
-	Storage  *redis.Client
+	Storage  redis.UniversalClient
 )
 
+// Expiration is held by one middleware and its owner, which changes it
+// without affecting other middlewares
+type Expiration struct {
+	d atomic.Int64
+}
+
+func NewExpiration(d time.Duration) *Expiration {
+	e := &Expiration{}
+	e.Set(d)
+
+	return e
+}
+
+// Set ignores non-positive durations
+func (e *Expiration) Set(d time.Duration) {
+	if d > 0 {
+		e.d.Store(int64(d))
+	}
+}
+
+func (e *Expiration) Get() time.Duration {
+	return time.Duration(e.d.Load())
+}
+
 func New(config ...Config) fiber.Handler {
 	cfg := configDefault(config...)
 	IDKey = cfg.IDKey
 	IDSource = strings.ToLower(cfg.IDSource)
 	DataKey = cfg.DataKey
 	Storage = cfg.Storage
+	expiration := cfg.ExpirationHandle
+	if expiration == nil {
+		expiration = NewExpiration(cfg.Expiration)
+	}
 
 	return func(c *fiber.Ctx) error {
 		if cfg.Next != nil && cfg.Next(c) {
@@ -96,6 +126,11 @@ func New(config ...Config) fiber.Handler {
 		// Context
 		c.Locals(cfg.IDKey, sessionID)
 		c.Locals(cfg.DataKey, data)
+		if cfg.ActorKey != "" {
+			if actor, ok := utils.ParseUUID(data.Get(cfg.ActorKey)); ok {
+				c.SetUserContext(defs.WithActor(c.UserContext(), actor))
+			}
+		}
 
 		// Go next
 		err = c.Next()
@@ -110,7 +145,7 @@ func New(config ...Config) fiber.Handler {
 		} else {
 			// Save data
 			src = data.Marshal()
-			err = cfg.Storage.Set(c.Context(), sessionID, src, cfg.Expiration).Err()
+			err = cfg.Storage.Set(c.Context(), sessionID, src, expiration.Get()).Err()
 		}
 
 		if errors.Is(err, redis.Nil) {
@@ -124,7 +159,7 @@ func New(config ...Config) fiber.Handler {
 			cookie := &fiber.Cookie{
 				Name:    cfg.IDKey,
 				Value:   sessionID,
-				Expires: time.Now().Add(cfg.Expiration),
+				Expires: time.Now().Add(expiration.Get()),
 			}
 			c.Cookie(cookie)
 		default:
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file config.go
+ * @package tenant
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package tenant
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type Config struct {
+	// Optional. Default: nil
+	Next func(c *fiber.Ctx) bool
+	// Merchant ID source, session or header. Header is only for trusted
+	// hops behind a gateway which overwrites it, platform merchant is
+	// rejected from header.
+	Source string
+	// Session data key or header name
+	Key string
+	// Reject requests without merchant ID
+	Strict bool
+}
+
+var ConfigDefault = Config{
+	Next:   nil,
+	Source: "session",
+	Key:    "merchant_id",
+	Strict: false,
+}
+
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	cfg := config[0]
+
+	if cfg.Source == "" {
+		cfg.Source = ConfigDefault.Source
+	}
+
+	if cfg.Key == "" {
+		if cfg.Source == "header" {
+			cfg.Key = "X-Merchant-ID"
+		} else {
+			cfg.Key = ConfigDefault.Key
+		}
+	}
+
+	return cfg
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
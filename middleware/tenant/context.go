@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file context.go
+ * @package tenant
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/zenkoo-live/svc.base/defs"
+)
+
+type (
+	merchantKey struct{}
+	unscopedKey struct{}
+)
+
+var (
+	ErrNoTenant         = errors.New("tenant: no merchant in context")
+	ErrUnscopedDenied   = errors.New("tenant: only platform merchant could run unscoped")
+	ErrMerchantMismatch = errors.New("tenant: merchant of model differs from context")
+)
+
+// WithMerchant returns ctx carrying merchant ID, which scopes queries of
+// Model
+func WithMerchant(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, merchantKey{}, id)
+}
+
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(merchantKey{}).(uuid.UUID)
+
+	return id, ok && id != uuid.Nil
+}
+
+// Unscoped opts out of merchant filter, only honored for
+// defs.PlatformMerchantID
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// scope returns merchant ID to filter with, uuid.Nil if unscoped
+func scope(ctx context.Context) (uuid.UUID, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrNoTenant
+	}
+
+	if unscoped, _ := ctx.Value(unscopedKey{}).(bool); unscoped {
+		if id != defs.PlatformMerchantID {
+			return uuid.Nil, ErrUnscopedDenied
+		}
+
+		return uuid.Nil, nil
+	}
+
+	return id, nil
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
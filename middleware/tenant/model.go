@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file model.go
+ * @package tenant
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package tenant
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+var (
+	_ bun.BeforeSelectHook = (*Model)(nil)
+	_ bun.BeforeInsertHook = (*Model)(nil)
+	_ bun.BeforeUpdateHook = (*Model)(nil)
+	_ bun.BeforeDeleteHook = (*Model)(nil)
+)
+
+// Model scopes selects, updates and deletes by merchant_id of context, and
+// fills it on insert. Embed it into merchant owned models.
+//
+// Merchant ID is filled by BeforeInsert and BeforeUpdate rather than
+// BeforeAppendModel, which defs.Entity defines. A model embedding both would
+// get two BeforeAppendModel at the same depth, and Go drops such ambiguous
+// methods, so neither hook would run.
+//
+// bun runs no select hook on Count and Exists, and runs Scan concurrently
+// with Count in ScanAndCount outside of transaction. Apply Scope to Count and
+// Exists, use Scan and Count separately instead of ScanAndCount.
+type Model struct {
+	MerchantID uuid.UUID `bun:"merchant_id,notnull" json:"merchant_id"`
+}
+
+// scoped is implemented by models embedding Model
+type scoped interface {
+	tenantModel() *Model
+}
+
+func (m *Model) tenantModel() *Model {
+	return m
+}
+
+// Scope adds merchant filter of ctx to q, fails without merchant in ctx
+func Scope(ctx context.Context, q bun.QueryBuilder) error {
+	id, err := scope(ctx)
+	if err != nil || id == uuid.Nil {
+		return err
+	}
+
+	q.Where("?TableAlias.merchant_id = ?", id)
+
+	return nil
+}
+
+func (*Model) BeforeSelect(ctx context.Context, q *bun.SelectQuery) error {
+	return Scope(ctx, q.QueryBuilder())
+}
+
+// BeforeInsert fills merchant ID of inserted models
+func (*Model) BeforeInsert(ctx context.Context, q *bun.InsertQuery) error {
+	return assign(ctx, q)
+}
+
+// BeforeUpdate scopes update, and refuses to move updated models to another
+// merchant
+func (*Model) BeforeUpdate(ctx context.Context, q *bun.UpdateQuery) error {
+	err := assign(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	return Scope(ctx, q.QueryBuilder())
+}
+
+func (*Model) BeforeDelete(ctx context.Context, q *bun.DeleteQuery) error {
+	return Scope(ctx, q.QueryBuilder())
+}
+
+// assign sets merchant ID of ctx on models of q. Hooks of bun are called on
+// zero value of model, so models are taken from q.
+func assign(ctx context.Context, q bun.Query) error {
+	var models []*Model
+	if model := q.GetModel(); model != nil {
+		models = collect(reflect.ValueOf(model.Value()))
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	id, err := scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range models {
+		switch {
+		case id == uuid.Nil:
+			// Unscoped platform keeps merchant of model
+			if m.MerchantID == uuid.Nil {
+				return ErrNoTenant
+			}
+		case m.MerchantID == uuid.Nil:
+			m.MerchantID = id
+		case m.MerchantID != id:
+			return ErrMerchantMismatch
+		}
+	}
+
+	return nil
+}
+
+// collect finds Model embedded in struct or slice of structs
+func collect(v reflect.Value) []*Model {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+
+		if s, ok := v.Interface().(scoped); ok {
+			return []*Model{s.tenantModel()}
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+
+	var models []*Model
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if elem.Kind() != reflect.Pointer {
+			elem = elem.Addr()
+		}
+
+		models = append(models, collect(elem)...)
+	}
+
+	return models
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file tenant.go
+ * @package tenant
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package tenant
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/zenkoo-live/svc.base/defs"
+	"github.com/zenkoo-live/svc.base/middleware/session"
+	"github.com/zenkoo-live/svc.base/utils"
+)
+
+// New returns handler putting merchant ID of request into user context. The
+// header source trusts the client, so it is only for services behind a
+// gateway which sets the header, and never accepts defs.PlatformMerchantID.
+func New(config ...Config) fiber.Handler {
+	cfg := configDefault(config...)
+	source := strings.ToLower(cfg.Source)
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		var v any
+		switch source {
+		case "header":
+			v = c.Get(cfg.Key)
+		default:
+			// Session
+			v = session.FromFiber(c).Get(cfg.Key)
+		}
+
+		id, ok := utils.ParseUUID(v)
+		if ok && source == "header" && id == defs.PlatformMerchantID {
+			resp := utils.WrapResponse(nil)
+			resp.SetStatus(fiber.StatusForbidden)
+			resp.SetCode(utils.CodeTenantDenied)
+			resp.SetMessage(utils.MsgTenantDenied)
+
+			return c.Status(resp.Status).JSON(resp)
+		}
+
+		if !ok && cfg.Strict {
+			resp := utils.WrapResponse(nil)
+			resp.SetStatus(fiber.StatusForbidden)
+			resp.SetCode(utils.CodeTenantRequired)
+			resp.SetMessage(utils.MsgTenantRequired)
+
+			return c.Status(resp.Status).JSON(resp)
+		}
+
+		if ok {
+			c.SetUserContext(WithMerchant(c.UserContext(), id))
+		}
+
+		return c.Next()
+	}
+}
+
+// FromFiber returns merchant ID of request, uuid.Nil if not set
+func FromFiber(c *fiber.Ctx) uuid.UUID {
+	id, _ := FromContext(c.UserContext())
+
+	return id
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
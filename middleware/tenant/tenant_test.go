@@ -0,0 +1,257 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file tenant_test.go
+ * @package tenant
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/defs"
+	"github.com/zenkoo-live/svc.base/internal/testdb"
+)
+
+type testOrder struct {
+	bun.BaseModel `bun:"table:orders,alias:o"`
+	Model
+
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+func testDB(t *testing.T) *bun.DB {
+	t.Helper()
+
+	db := testdb.SQLite(t)
+	_, err := db.NewCreateTable().Model((*testOrder)(nil)).Exec(context.Background())
+	if err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	return db
+}
+
+func TestScope(t *testing.T) {
+	merchant := uuid.New()
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want uuid.UUID
+		err  error
+	}{
+		{"none", context.Background(), uuid.Nil, ErrNoTenant},
+		{"nil merchant", WithMerchant(context.Background(), uuid.Nil), uuid.Nil, ErrNoTenant},
+		{"merchant", WithMerchant(context.Background(), merchant), merchant, nil},
+		{"unscoped merchant", Unscoped(WithMerchant(context.Background(), merchant)), uuid.Nil, ErrUnscopedDenied},
+		{"platform", WithMerchant(context.Background(), defs.PlatformMerchantID), defs.PlatformMerchantID, nil},
+		{"unscoped platform", Unscoped(WithMerchant(context.Background(), defs.PlatformMerchantID)), uuid.Nil, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := scope(c.ctx)
+			if !errors.Is(err, c.err) || got != c.want {
+				t.Errorf("scope = %s, %v, want %s, %v", got, err, c.want, c.err)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	one := &testOrder{}
+	list := []testOrder{{}, {}}
+	ptrs := []*testOrder{{}, {}, {}}
+
+	cases := []struct {
+		name  string
+		value any
+		want  int
+	}{
+		{"struct", one, 1},
+		{"slice", &list, 2},
+		{"pointer slice", &ptrs, 3},
+		{"nil", (*testOrder)(nil), 0},
+		{"unscoped", &struct{ ID int64 }{}, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := collect(reflect.ValueOf(c.value)); len(got) != c.want {
+				t.Errorf("collected %d models, want %d", len(got), c.want)
+			}
+		})
+	}
+}
+
+func TestModelHooks(t *testing.T) {
+	db := testDB(t)
+	a, b := uuid.New(), uuid.New()
+	ctxA := WithMerchant(context.Background(), a)
+	ctxB := WithMerchant(context.Background(), b)
+
+	orders := []testOrder{{Name: "a1"}, {Name: "a2"}}
+	_, err := db.NewInsert().Model(&orders).Exec(ctxA)
+	if err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	for _, o := range orders {
+		if o.MerchantID != a {
+			t.Errorf("inserted with merchant %s, want %s", o.MerchantID, a)
+		}
+	}
+
+	_, err = db.NewInsert().Model(&testOrder{Name: "b1"}).Exec(ctxB)
+	if err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	_, err = db.NewInsert().Model(&testOrder{Model: Model{MerchantID: b}, Name: "forged"}).Exec(ctxA)
+	if !errors.Is(err, ErrMerchantMismatch) {
+		t.Errorf("insert for other merchant: %v", err)
+	}
+
+	_, err = db.NewInsert().Model(&testOrder{Name: "anonymous"}).Exec(context.Background())
+	if !errors.Is(err, ErrNoTenant) {
+		t.Errorf("insert without merchant: %v", err)
+	}
+
+	var found []testOrder
+	err = db.NewSelect().Model(&found).Scan(ctxB)
+	if err != nil || len(found) != 1 || found[0].Name != "b1" {
+		t.Errorf("select of b: %v, %v", found, err)
+	}
+
+	res, err := db.NewUpdate().Model((*testOrder)(nil)).Set("name = ?", "x").Where("1 = 1").Exec(ctxB)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("update of b touched %d rows", n)
+	}
+
+	orders[0].MerchantID = b
+	_, err = db.NewUpdate().Model(&orders[0]).WherePK().Exec(ctxA)
+	if !errors.Is(err, ErrMerchantMismatch) {
+		t.Errorf("moving model to other merchant: %v", err)
+	}
+
+	res, err = db.NewDelete().Model((*testOrder)(nil)).Where("1 = 1").Exec(ctxA)
+	if err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("delete of a touched %d rows", n)
+	}
+
+	platform := Unscoped(WithMerchant(context.Background(), defs.PlatformMerchantID))
+	found = nil
+	err = db.NewSelect().Model(&found).Scan(platform)
+	if err != nil || len(found) != 1 {
+		t.Errorf("unscoped select: %v, %v", found, err)
+	}
+
+	err = db.NewSelect().Model(&found).Scan(Unscoped(ctxA))
+	if !errors.Is(err, ErrUnscopedDenied) {
+		t.Errorf("unscoped select of merchant: %v", err)
+	}
+}
+
+type testEntityOrder struct {
+	bun.BaseModel `bun:"table:entity_orders"`
+	defs.Entity
+	Model
+
+	Name string
+}
+
+func TestModelWithEntity(t *testing.T) {
+	db := testDB(t)
+	ctx := WithMerchant(context.Background(), uuid.New())
+	_, err := db.NewCreateTable().Model((*testEntityOrder)(nil)).Exec(ctx)
+	if err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	o := &testEntityOrder{Name: "o"}
+	_, err = db.NewInsert().Model(o).Exec(ctx)
+	if err != nil {
+		t.Fatalf("insert: %s", err)
+	}
+
+	if o.MerchantID == uuid.Nil || o.ID == uuid.Nil || o.CreatedAt.IsZero() {
+		t.Errorf("hooks of tenant and entity not both run: %+v", o)
+	}
+}
+
+func TestHeaderSource(t *testing.T) {
+	merchant := uuid.New()
+	cases := []struct {
+		name   string
+		header string
+		strict bool
+		status int
+		want   uuid.UUID
+	}{
+		{"merchant", merchant.String(), false, fiber.StatusOK, merchant},
+		{"missing", "", false, fiber.StatusOK, uuid.Nil},
+		{"missing strict", "", true, fiber.StatusForbidden, uuid.Nil},
+		{"invalid strict", "nope", true, fiber.StatusForbidden, uuid.Nil},
+		{"platform", defs.PlatformMerchantID.String(), false, fiber.StatusForbidden, uuid.Nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got uuid.UUID
+
+			app := fiber.New()
+			app.Use(New(Config{Source: "header", Strict: c.strict}))
+			app.Get("/", func(ctx *fiber.Ctx) error {
+				got = FromFiber(ctx)
+
+				return nil
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if c.header != "" {
+				req.Header.Set("X-Merchant-ID", c.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != c.status || got != c.want {
+				t.Errorf("status %d, merchant %s, want %d, %s", resp.StatusCode, got, c.status, c.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
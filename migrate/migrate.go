@@ -0,0 +1,330 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file migrate.go
+ * @package migrate
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/migrate"
+)
+
+const (
+	DefaultTable       = "bun_migrations"
+	DefaultLocksTable  = "bun_migration_locks"
+	DefaultLockTimeout = 60 * time.Second
+	DefaultLockTTL     = 30 * time.Second
+
+	lockPollInterval = time.Second
+)
+
+type (
+	Migrations     = migrate.Migrations
+	Migration      = migrate.Migration
+	MigrationSlice = migrate.MigrationSlice
+	MigrationGroup = migrate.MigrationGroup
+	MigrationFunc  = migrate.MigrationFunc
+)
+
+// migrationLock replaces bun's lock model, whose NVARCHAR(MAX) unique
+// column cannot be indexed by mssql. It is a lease renewed by its owner,
+// taken over by others once expired.
+type migrationLock struct {
+	ID        int64     `bun:",pk,autoincrement"`
+	TableName string    `bun:"type:varchar(255),notnull,unique"`
+	Owner     string    `bun:"type:varchar(64),notnull"`
+	LockedAt  time.Time `bun:",notnull"`
+}
+
+// NewMigrations collects SQL migrations from fsys (usually an embed.FS),
+// named like 20240508120000_create_users.up.sql / .down.sql, .tx.up.sql runs
+// in transaction. Go migrations are added to the result by MustRegister in
+// the file named after the migration.
+func NewMigrations(fsys fs.FS) (*Migrations, error) {
+	ms := migrate.NewMigrations()
+	if fsys != nil {
+		err := ms.Discover(fsys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ms, nil
+}
+
+type Option func(*Migrator)
+
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+func WithLocksTable(table string) Option {
+	return func(m *Migrator) {
+		m.locksTable = table
+	}
+}
+
+// WithLockTimeout sets how long Lock waits for another instance to finish
+func WithLockTimeout(d time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = d
+	}
+}
+
+// WithLockTTL sets how long a lock not renewed by its owner, like a crashed
+// instance, blocks others
+func WithLockTTL(d time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTTL = d
+	}
+}
+
+type Migrator struct {
+	db          *bun.DB
+	bm          *migrate.Migrator
+	table       string
+	locksTable  string
+	lockTimeout time.Duration
+	lockTTL     time.Duration
+	// Holder of lock, and stop of its renewal
+	owner string
+	renew chan struct{}
+}
+
+func NewMigrator(db *bun.DB, ms *Migrations, opts ...Option) *Migrator {
+	m := &Migrator{
+		db:          db,
+		table:       DefaultTable,
+		locksTable:  DefaultLocksTable,
+		lockTimeout: DefaultLockTimeout,
+		lockTTL:     DefaultLockTTL,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.bm = migrate.NewMigrator(db, ms, migrate.WithTableName(m.table), migrate.WithLocksTableName(m.locksTable))
+
+	return m
+}
+
+func (m *Migrator) DB() *bun.DB {
+	return m.db
+}
+
+// Init creates migration and lock tables if not exist
+func (m *Migrator) Init(ctx context.Context) error {
+	err := m.createTable(ctx, (*Migration)(nil), m.table)
+	if err != nil {
+		return fmt.Errorf("migrate: create table %s : %w", m.table, err)
+	}
+
+	err = m.createTable(ctx, (*migrationLock)(nil), m.locksTable)
+	if err != nil {
+		return fmt.Errorf("migrate: create table %s : %w", m.locksTable, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) createTable(ctx context.Context, model any, table string) error {
+	q := m.db.NewCreateTable().Model(model).ModelTableExpr(table)
+	if m.db.HasFeature(feature.TableNotExists) {
+		_, err := q.IfNotExists().Exec(ctx)
+
+		return err
+	}
+
+	// mssql has no CREATE TABLE IF NOT EXISTS
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = table[i+1:]
+	}
+
+	var n int
+	err := m.db.NewRaw("SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_NAME = ?", table).Scan(ctx, &n)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	_, err = q.Exec(ctx)
+
+	return err
+}
+
+// Lock acquires migration lock, waits until it is released by another
+// instance or lock timeout reached. The lock is renewed until Unlock, a lock
+// left by a crashed instance is taken over after lock TTL.
+func (m *Migrator) Lock(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+	defer cancel()
+
+	lock := &migrationLock{TableName: m.table, Owner: uuid.NewString()}
+	for {
+		lock.LockedAt = time.Now().UTC()
+		_, err := m.db.NewInsert().Model(lock).ModelTableExpr(m.locksTable).Exec(ctx)
+		if err == nil {
+			m.owner = lock.Owner
+			m.renew = make(chan struct{})
+			go m.renewLock(lock.Owner, m.renew)
+
+			return nil
+		}
+
+		// Insert failed for other reason than an existing lock
+		held, herr := m.db.NewSelect().
+			TableExpr(m.locksTable).
+			Where("? = ?", bun.Ident("table_name"), m.table).
+			Exists(ctx)
+		if herr != nil || !held {
+			return fmt.Errorf("migrate: lock table %s : %w", m.table, errors.Join(err, herr))
+		}
+
+		// Expired lease
+		_, derr := m.db.NewDelete().
+			Model((*migrationLock)(nil)).
+			ModelTableExpr(m.locksTable).
+			Where("? = ?", bun.Ident("table_name"), m.table).
+			Where("? < ?", bun.Ident("locked_at"), time.Now().UTC().Add(-m.lockTTL)).
+			Exec(ctx)
+		if derr == nil {
+			err = errors.New("locked by another instance")
+		}
+
+		select {
+		case <-time.After(lockPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("migrate: table %s is locked : %w", m.table, errors.Join(ctx.Err(), err))
+		}
+	}
+}
+
+// renewLock refreshes lease of owner, until stop closed
+func (m *Migrator) renewLock(owner string, stop chan struct{}) {
+	ticker := time.NewTicker(m.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), m.lockTTL/3)
+			m.db.NewUpdate().
+				Model((*migrationLock)(nil)).
+				ModelTableExpr(m.locksTable).
+				Set("? = ?", bun.Ident("locked_at"), time.Now().UTC()).
+				Where("? = ?", bun.Ident("table_name"), m.table).
+				Where("? = ?", bun.Ident("owner"), owner).
+				Exec(ctx)
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Unlock releases lock held by this migrator, without owner it removes any
+// lock of the table, which clears one left behind manually
+func (m *Migrator) Unlock(ctx context.Context) error {
+	q := m.db.NewDelete().
+		Model((*migrationLock)(nil)).
+		ModelTableExpr(m.locksTable).
+		Where("? = ?", bun.Ident("table_name"), m.table)
+	if m.owner != "" {
+		q.Where("? = ?", bun.Ident("owner"), m.owner)
+		close(m.renew)
+		m.owner, m.renew = "", nil
+	}
+
+	_, err := q.Exec(ctx)
+
+	return err
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	err := m.Init(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = m.Lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Release even if ctx cancelled, or other instances wait for nothing
+	defer m.Unlock(context.WithoutCancel(ctx))
+
+	return fn()
+}
+
+// Up applies all pending migrations as a new group
+func (m *Migrator) Up(ctx context.Context) (group *MigrationGroup, err error) {
+	err = m.locked(ctx, func() error {
+		group, err = m.bm.Migrate(ctx)
+
+		return err
+	})
+
+	return
+}
+
+// Down rolls back the last applied group
+func (m *Migrator) Down(ctx context.Context) (group *MigrationGroup, err error) {
+	err = m.locked(ctx, func() error {
+		group, err = m.bm.Rollback(ctx)
+
+		return err
+	})
+
+	return
+}
+
+// MarkApplied records all pending migrations as applied without running them
+func (m *Migrator) MarkApplied(ctx context.Context) (group *MigrationGroup, err error) {
+	err = m.locked(ctx, func() error {
+		group, err = m.bm.Migrate(ctx, migrate.WithNopMigration())
+
+		return err
+	})
+
+	return
+}
+
+// Status returns all migrations in ascending order, applied ones have ID
+func (m *Migrator) Status(ctx context.Context) (MigrationSlice, error) {
+	err := m.Init(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.bm.MigrationsWithStatus(ctx)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
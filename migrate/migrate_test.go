@@ -0,0 +1,276 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file migrate_test.go
+ * @package migrate
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package migrate
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/internal/testdb"
+)
+
+var empty, _ = NewMigrations(nil)
+
+func TestLockExclusive(t *testing.T) {
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	err := NewMigrator(db, empty).Init(ctx)
+	if err != nil {
+		t.Fatalf("init: %s", err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		holders atomic.Int32
+		maxSeen atomic.Int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			m := NewMigrator(db, empty, WithLockTimeout(10*time.Second))
+			err := m.Lock(ctx)
+			if err != nil {
+				t.Errorf("lock: %s", err)
+
+				return
+			}
+
+			n := holders.Add(1)
+			if n > maxSeen.Load() {
+				maxSeen.Store(n)
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			holders.Add(-1)
+			err = m.Unlock(ctx)
+			if err != nil {
+				t.Errorf("unlock: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if maxSeen.Load() != 1 {
+		t.Errorf("lock held by %d migrators at once", maxSeen.Load())
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+
+	holder := NewMigrator(db, empty)
+	err := holder.Init(ctx)
+	if err == nil {
+		err = holder.Lock(ctx)
+	}
+
+	if err != nil {
+		t.Fatalf("lock: %s", err)
+	}
+
+	defer holder.Unlock(ctx)
+
+	err = NewMigrator(db, empty, WithLockTimeout(1500*time.Millisecond)).Lock(ctx)
+	if err == nil {
+		t.Fatal("lock of held table succeeded")
+	}
+}
+
+func TestLockTakeover(t *testing.T) {
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	m := NewMigrator(db, empty, WithLockTTL(time.Second), WithLockTimeout(5*time.Second))
+	err := m.Init(ctx)
+	if err != nil {
+		t.Fatalf("init: %s", err)
+	}
+
+	// Left by a crashed instance
+	stale := &migrationLock{TableName: DefaultTable, Owner: "crashed", LockedAt: time.Now().UTC().Add(-time.Minute)}
+	_, err = db.NewInsert().Model(stale).ModelTableExpr(DefaultLocksTable).Exec(ctx)
+	if err != nil {
+		t.Fatalf("insert stale lock: %s", err)
+	}
+
+	err = m.Lock(ctx)
+	if err != nil {
+		t.Fatalf("take over stale lock: %s", err)
+	}
+
+	// Renewed lease is not taken over
+	time.Sleep(1500 * time.Millisecond)
+	err = NewMigrator(db, empty, WithLockTTL(time.Second), WithLockTimeout(1500*time.Millisecond)).Lock(ctx)
+	if err == nil {
+		t.Fatal("renewed lock taken over")
+	}
+
+	err = m.Unlock(ctx)
+	if err != nil {
+		t.Fatalf("unlock: %s", err)
+	}
+
+	n, err := db.NewSelect().Model((*migrationLock)(nil)).ModelTableExpr(DefaultLocksTable).Count(ctx)
+	if err != nil || n != 0 {
+		t.Errorf("locks left after unlock: %d, %v", n, err)
+	}
+}
+
+func TestLockError(t *testing.T) {
+	db := testdb.SQLite(t)
+
+	// No locks table, insert fails without any lock held
+	start := time.Now()
+	err := NewMigrator(db, empty).Lock(context.Background())
+	if err == nil {
+		t.Fatal("lock without table succeeded")
+	}
+
+	if time.Since(start) > lockPollInterval {
+		t.Errorf("lock error returned after %s, want at once", time.Since(start))
+	}
+}
+
+var testFS = fstest.MapFS{
+	"20240101000000_users.up.sql":      {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")},
+	"20240101000000_users.down.sql":    {Data: []byte("DROP TABLE users")},
+	"20240102000000_items.tx.up.sql":   {Data: []byte("CREATE TABLE items (id INTEGER PRIMARY KEY)")},
+	"20240102000000_items.tx.down.sql": {Data: []byte("DROP TABLE items")},
+}
+
+func testMigrator(t *testing.T, db *bun.DB, opts ...Option) *Migrator {
+	t.Helper()
+
+	ms, err := NewMigrations(testFS)
+	if err != nil {
+		t.Fatalf("discover: %s", err)
+	}
+
+	return NewMigrator(db, ms, opts...)
+}
+
+func tableExists(t *testing.T, db *bun.DB, table string) bool {
+	t.Helper()
+
+	var n int
+	err := db.NewRaw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(context.Background(), &n)
+	if err != nil {
+		t.Fatalf("check table %s: %s", table, err)
+	}
+
+	return n > 0
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	m := testMigrator(t, db)
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %s", err)
+	}
+
+	if len(status) != 2 || len(status.Applied()) != 0 {
+		t.Fatalf("status before up: %s", status)
+	}
+
+	group, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %s", err)
+	}
+
+	if group.IsZero() || len(group.Migrations) != 2 {
+		t.Fatalf("up applied %s", group)
+	}
+
+	if !tableExists(t, db, "users") || !tableExists(t, db, "items") {
+		t.Fatal("tables not created by up")
+	}
+
+	// Nothing pending
+	group, err = m.Up(ctx)
+	if err != nil || !group.IsZero() {
+		t.Fatalf("second up: %s, %v", group, err)
+	}
+
+	status, err = m.Status(ctx)
+	if err != nil || len(status.Applied()) != 2 {
+		t.Fatalf("status after up: %s, %v", status, err)
+	}
+
+	group, err = m.Down(ctx)
+	if err != nil {
+		t.Fatalf("down: %s", err)
+	}
+
+	if len(group.Migrations) != 2 || tableExists(t, db, "users") || tableExists(t, db, "items") {
+		t.Fatalf("down rolled back %s", group)
+	}
+
+	status, err = m.Status(ctx)
+	if err != nil || len(status.Applied()) != 0 {
+		t.Fatalf("status after down: %s, %v", status, err)
+	}
+
+	// Lock released after each operation
+	n, err := db.NewSelect().Model((*migrationLock)(nil)).ModelTableExpr(DefaultLocksTable).Count(ctx)
+	if err != nil || n != 0 {
+		t.Errorf("locks left: %d, %v", n, err)
+	}
+}
+
+func TestMigrateMarkApplied(t *testing.T) {
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	m := testMigrator(t, db, WithTable("schema_versions"), WithLocksTable("schema_locks"))
+
+	group, err := m.MarkApplied(ctx)
+	if err != nil {
+		t.Fatalf("mark applied: %s", err)
+	}
+
+	if len(group.Migrations) != 2 {
+		t.Fatalf("marked %s", group)
+	}
+
+	if tableExists(t, db, "users") {
+		t.Error("mark applied ran migration")
+	}
+
+	if !tableExists(t, db, "schema_versions") || !tableExists(t, db, "schema_locks") {
+		t.Error("custom tables not created")
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil || len(status.Unapplied()) != 0 {
+		t.Fatalf("status after mark applied: %s, %v", status, err)
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,659 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file mongoschema.go
+ * @package mongoschema
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package mongoschema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	DefaultCollection      = "_migrations"
+	DefaultLocksCollection = "_migrations_lock"
+	DefaultLockTimeout     = 60 * time.Second
+	DefaultLockTTL         = 30 * time.Second
+
+	lockPollInterval = time.Second
+
+	// Server error code of creating an existing collection
+	codeNamespaceExists = 48
+)
+
+// Index declares one index, Name defaults to mongo's generated name like
+// status_1_created_at_-1
+type Index struct {
+	Name   string
+	Keys   bson.D
+	Unique bool
+	Sparse bool
+	// TTL on a single date field, zero is not TTL
+	ExpireAfter   time.Duration
+	PartialFilter bson.D
+}
+
+func (i *Index) name() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	parts := make([]string, 0, len(i.Keys))
+	for _, k := range i.Keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", k.Key, k.Value))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+func (i *Index) model() mongo.IndexModel {
+	opts := options.Index().SetName(i.name())
+	if i.Unique {
+		opts.SetUnique(true)
+	}
+
+	if i.Sparse {
+		opts.SetSparse(true)
+	}
+
+	if i.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(i.ExpireAfter / time.Second))
+	}
+
+	if i.PartialFilter != nil {
+		opts.SetPartialFilterExpression(i.PartialFilter)
+	}
+
+	return mongo.IndexModel{Keys: i.Keys, Options: opts}
+}
+
+// Collection declares a collection with its indexes and validator
+type Collection struct {
+	Name    string
+	Indexes []Index
+	// Document of $jsonSchema, nil for no validator
+	JSONSchema bson.M
+	// strict (server default) or moderate
+	ValidationLevel string
+	// error (server default) or warn
+	ValidationAction string
+}
+
+type MigrationFunc func(ctx context.Context, db *mongo.Database) error
+
+// Migration is a versioned data migration, applied in order of Name, like
+// 20240508120000_backfill_status
+type Migration struct {
+	Name string        `bson:"_id"`
+	Up   MigrationFunc `bson:"-"`
+	// Zero if not applied
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+func (m *Migration) IsApplied() bool {
+	return !m.AppliedAt.IsZero()
+}
+
+// Schema holds declared collections and migrations of a database
+type Schema struct {
+	collections []*Collection
+	migrations  []*Migration
+}
+
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+func (s *Schema) AddCollection(cs ...*Collection) *Schema {
+	s.collections = append(s.collections, cs...)
+
+	return s
+}
+
+func (s *Schema) AddMigration(name string, up MigrationFunc) *Schema {
+	s.migrations = append(s.migrations, &Migration{Name: name, Up: up})
+
+	return s
+}
+
+// Drift is a difference between declared schema and database, which is
+// reported but never changed by Reconcile
+type Drift struct {
+	Collection string
+	// Empty for validator drift
+	Index  string
+	Reason string
+}
+
+func (d Drift) String() string {
+	if d.Index == "" {
+		return fmt.Sprintf("%s: %s", d.Collection, d.Reason)
+	}
+
+	return fmt.Sprintf("%s.%s: %s", d.Collection, d.Index, d.Reason)
+}
+
+// Report is the result of Reconcile, indexes named as collection.index
+type Report struct {
+	Collections []string
+	Indexes     []string
+	Drifts      []Drift
+}
+
+func (r *Report) IsZero() bool {
+	return len(r.Collections) == 0 && len(r.Indexes) == 0 && len(r.Drifts) == 0
+}
+
+type Option func(*Migrator)
+
+func WithCollection(name string) Option {
+	return func(m *Migrator) {
+		m.collection = name
+	}
+}
+
+func WithLocksCollection(name string) Option {
+	return func(m *Migrator) {
+		m.locksCollection = name
+	}
+}
+
+// WithLockTimeout sets how long Lock waits for another instance to finish
+func WithLockTimeout(d time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = d
+	}
+}
+
+// WithLockTTL sets how long a lock not renewed by its owner, like a crashed
+// instance, blocks others
+func WithLockTTL(d time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTTL = d
+	}
+}
+
+type Migrator struct {
+	db              *mongo.Database
+	schema          *Schema
+	collection      string
+	locksCollection string
+	lockTimeout     time.Duration
+	lockTTL         time.Duration
+
+	// Lock held by this migrator, renewed until renew closed
+	owner string
+	renew chan struct{}
+}
+
+func NewMigrator(db *mongo.Database, s *Schema, opts ...Option) *Migrator {
+	m := &Migrator{
+		db:              db,
+		schema:          s,
+		collection:      DefaultCollection,
+		locksCollection: DefaultLocksCollection,
+		lockTimeout:     DefaultLockTimeout,
+		lockTTL:         DefaultLockTTL,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *Migrator) DB() *mongo.Database {
+	return m.db
+}
+
+// Lock acquires migration lock, waits until it is released by another
+// instance or lock timeout reached. The lock is a lease renewed while held,
+// one not renewed within lock TTL is left by a crashed instance and taken
+// over.
+func (m *Migrator) Lock(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+	defer cancel()
+
+	locks := m.db.Collection(m.locksCollection)
+	owner := uuid.NewString()
+	for {
+		lock := bson.D{
+			{Key: "_id", Value: m.collection},
+			{Key: "owner", Value: owner},
+			{Key: "locked_at", Value: time.Now()},
+		}
+		_, err := locks.InsertOne(ctx, lock)
+		if err == nil {
+			m.owner = owner
+			m.renew = make(chan struct{})
+			go m.renewLock(owner, m.renew)
+
+			return nil
+		}
+
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("mongoschema: lock %s : %w", m.collection, err)
+		}
+
+		// Expired lease
+		_, err = locks.DeleteOne(ctx, bson.D{
+			{Key: "_id", Value: m.collection},
+			{Key: "locked_at", Value: bson.D{{Key: "$lt", Value: time.Now().Add(-m.lockTTL)}}},
+		})
+		if err == nil {
+			err = errors.New("locked by another instance")
+		}
+
+		select {
+		case <-time.After(lockPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("mongoschema: %s is locked : %w", m.collection, errors.Join(ctx.Err(), err))
+		}
+	}
+}
+
+// renewLock refreshes lease of owner, until stop closed
+func (m *Migrator) renewLock(owner string, stop chan struct{}) {
+	ticker := time.NewTicker(m.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), m.lockTTL/3)
+			m.db.Collection(m.locksCollection).UpdateOne(
+				ctx,
+				bson.D{{Key: "_id", Value: m.collection}, {Key: "owner", Value: owner}},
+				bson.D{{Key: "$set", Value: bson.D{{Key: "locked_at", Value: time.Now()}}}},
+			)
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Unlock releases lock held by this migrator, without owner it removes any
+// lock of the collection, which clears one left behind manually
+func (m *Migrator) Unlock(ctx context.Context) error {
+	filter := bson.D{{Key: "_id", Value: m.collection}}
+	if m.owner != "" {
+		filter = append(filter, bson.E{Key: "owner", Value: m.owner})
+		close(m.renew)
+		m.owner, m.renew = "", nil
+	}
+
+	_, err := m.db.Collection(m.locksCollection).DeleteOne(ctx, filter)
+
+	return err
+}
+
+func (m *Migrator) locked(ctx context.Context, fn func() error) error {
+	err := m.Lock(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Release even if ctx cancelled, or other instances wait for nothing
+	defer m.Unlock(context.WithoutCancel(ctx))
+
+	return fn()
+}
+
+// Reconcile creates missing collections and indexes, and reports drift of
+// existing ones. Validators are only set on new collections.
+func (m *Migrator) Reconcile(ctx context.Context) (report *Report, err error) {
+	err = m.locked(ctx, func() error {
+		report, err = m.reconcile(ctx)
+
+		return err
+	})
+
+	return
+}
+
+type collectionOptions struct {
+	Validator        bson.D `bson:"validator"`
+	ValidationLevel  string `bson:"validationLevel"`
+	ValidationAction string `bson:"validationAction"`
+}
+
+type indexSpec struct {
+	Name               string `bson:"name"`
+	Key                bson.D `bson:"key"`
+	Unique             bool   `bson:"unique"`
+	Sparse             bool   `bson:"sparse"`
+	ExpireAfterSeconds *int64 `bson:"expireAfterSeconds"`
+	PartialFilter      bson.D `bson:"partialFilterExpression"`
+}
+
+func (m *Migrator) reconcile(ctx context.Context) (*Report, error) {
+	report := &Report{}
+
+	specs, err := m.db.ListCollectionSpecifications(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("mongoschema: list collections : %w", err)
+	}
+
+	existing := make(map[string]*mongo.CollectionSpecification, len(specs))
+	for _, spec := range specs {
+		existing[spec.Name] = spec
+	}
+
+	var errs error
+	for _, c := range m.schema.collections {
+		spec, ok := existing[c.Name]
+		if ok {
+			report.Drifts = append(report.Drifts, validatorDrift(c, spec)...)
+		} else {
+			err = m.createCollection(ctx, c)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("mongoschema: create collection %s : %w", c.Name, err))
+
+				continue
+			}
+
+			report.Collections = append(report.Collections, c.Name)
+		}
+
+		err = m.reconcileIndexes(ctx, c, report)
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return report, errs
+}
+
+func (m *Migrator) createCollection(ctx context.Context, c *Collection) error {
+	opts := options.CreateCollection()
+	if c.JSONSchema != nil {
+		opts.SetValidator(bson.D{{Key: "$jsonSchema", Value: c.JSONSchema}})
+		if c.ValidationLevel != "" {
+			opts.SetValidationLevel(c.ValidationLevel)
+		}
+
+		if c.ValidationAction != "" {
+			opts.SetValidationAction(c.ValidationAction)
+		}
+	}
+
+	err := m.db.CreateCollection(ctx, c.Name, opts)
+
+	// Created by another instance without lock
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorCode(codeNamespaceExists) {
+		return nil
+	}
+
+	return err
+}
+
+func validatorDrift(c *Collection, spec *mongo.CollectionSpecification) []Drift {
+	var opts collectionOptions
+	if len(spec.Options) > 0 {
+		err := bson.Unmarshal(spec.Options, &opts)
+		if err != nil {
+			return []Drift{{Collection: c.Name, Reason: "unreadable options : " + err.Error()}}
+		}
+	}
+
+	var current any
+	for _, e := range opts.Validator {
+		if e.Key == "$jsonSchema" {
+			current = e.Value
+		}
+	}
+
+	var drifts []Drift
+	switch {
+	case c.JSONSchema == nil && opts.Validator != nil:
+		drifts = append(drifts, Drift{Collection: c.Name, Reason: "validator not declared"})
+	case c.JSONSchema != nil && current == nil:
+		drifts = append(drifts, Drift{Collection: c.Name, Reason: "validator missing"})
+	case c.JSONSchema != nil && !same(c.JSONSchema, current):
+		drifts = append(drifts, Drift{Collection: c.Name, Reason: "validator differs"})
+	}
+
+	if c.JSONSchema != nil && current != nil {
+		// Server omits defaults
+		if opts.ValidationLevel == "" {
+			opts.ValidationLevel = "strict"
+		}
+
+		if opts.ValidationAction == "" {
+			opts.ValidationAction = "error"
+		}
+
+		if c.ValidationLevel != "" && !strings.EqualFold(c.ValidationLevel, opts.ValidationLevel) {
+			drifts = append(drifts, Drift{Collection: c.Name, Reason: fmt.Sprintf("validation level is %s", opts.ValidationLevel)})
+		}
+
+		if c.ValidationAction != "" && !strings.EqualFold(c.ValidationAction, opts.ValidationAction) {
+			drifts = append(drifts, Drift{Collection: c.Name, Reason: fmt.Sprintf("validation action is %s", opts.ValidationAction)})
+		}
+	}
+
+	return drifts
+}
+
+func (m *Migrator) reconcileIndexes(ctx context.Context, c *Collection, report *Report) error {
+	coll := m.db.Collection(c.Name)
+	cur, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("mongoschema: list indexes of %s : %w", c.Name, err)
+	}
+
+	var specs []indexSpec
+	err = cur.All(ctx, &specs)
+	if err != nil {
+		return fmt.Errorf("mongoschema: list indexes of %s : %w", c.Name, err)
+	}
+
+	existing := make(map[string]*indexSpec, len(specs))
+	for i := range specs {
+		existing[specs[i].Name] = &specs[i]
+	}
+
+	var (
+		errs     error
+		declared = make(map[string]bool, len(c.Indexes))
+	)
+	for i := range c.Indexes {
+		idx := &c.Indexes[i]
+		name := idx.name()
+		declared[name] = true
+		if spec, ok := existing[name]; ok {
+			if reason := indexDrift(idx, spec); reason != "" {
+				report.Drifts = append(report.Drifts, Drift{Collection: c.Name, Index: name, Reason: reason})
+			}
+
+			continue
+		}
+
+		_, err = coll.Indexes().CreateOne(ctx, idx.model())
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("mongoschema: create index %s.%s : %w", c.Name, name, err))
+
+			continue
+		}
+
+		report.Indexes = append(report.Indexes, c.Name+"."+name)
+	}
+
+	for _, spec := range specs {
+		if spec.Name != "_id_" && !declared[spec.Name] {
+			report.Drifts = append(report.Drifts, Drift{Collection: c.Name, Index: spec.Name, Reason: "index not declared"})
+		}
+	}
+
+	return errs
+}
+
+// indexDrift returns the first difference of idx and spec, empty if same
+func indexDrift(idx *Index, spec *indexSpec) string {
+	if len(idx.Keys) != len(spec.Key) {
+		return "keys differ"
+	}
+
+	for i, k := range idx.Keys {
+		if k.Key != spec.Key[i].Key || !same(k.Value, spec.Key[i].Value) {
+			return "keys differ"
+		}
+	}
+
+	if idx.Unique != spec.Unique {
+		return fmt.Sprintf("unique is %t", spec.Unique)
+	}
+
+	if idx.Sparse != spec.Sparse {
+		return fmt.Sprintf("sparse is %t", spec.Sparse)
+	}
+
+	ttl := int64(idx.ExpireAfter / time.Second)
+	switch {
+	case spec.ExpireAfterSeconds == nil && ttl > 0:
+		return "not a TTL index"
+	case spec.ExpireAfterSeconds != nil && *spec.ExpireAfterSeconds != ttl:
+		return fmt.Sprintf("expires after %ds", *spec.ExpireAfterSeconds)
+	}
+
+	if (idx.PartialFilter == nil) != (spec.PartialFilter == nil) || (idx.PartialFilter != nil && !same(idx.PartialFilter, spec.PartialFilter)) {
+		return "partial filter differs"
+	}
+
+	return ""
+}
+
+// same compares x and y as relaxed extended JSON, which ignores numeric
+// types and order of map keys
+func same(x, y any) bool {
+	return reflect.DeepEqual(canonical(x), canonical(y))
+}
+
+func canonical(v any) any {
+	src, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false, false)
+	if err != nil {
+		return nil
+	}
+
+	var ret any
+	json.Unmarshal(src, &ret)
+
+	return ret
+}
+
+// Up applies pending migrations in order of name, returns applied ones. It
+// stops at the first failure, migrations before it stay applied.
+func (m *Migrator) Up(ctx context.Context) (applied []*Migration, err error) {
+	err = m.locked(ctx, func() error {
+		applied, err = m.apply(ctx, true)
+
+		return err
+	})
+
+	return
+}
+
+// MarkApplied records all pending migrations as applied without running them
+func (m *Migrator) MarkApplied(ctx context.Context) (applied []*Migration, err error) {
+	err = m.locked(ctx, func() error {
+		applied, err = m.apply(ctx, false)
+
+		return err
+	})
+
+	return
+}
+
+func (m *Migrator) apply(ctx context.Context, run bool) ([]*Migration, error) {
+	all, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []*Migration
+	for _, mg := range all {
+		if mg.IsApplied() {
+			continue
+		}
+
+		if run && mg.Up != nil {
+			err = mg.Up(ctx, m.db)
+			if err != nil {
+				return applied, fmt.Errorf("mongoschema: migration %s : %w", mg.Name, err)
+			}
+		}
+
+		mg.AppliedAt = time.Now()
+		_, err = m.db.Collection(m.collection).InsertOne(ctx, mg)
+		if err != nil {
+			return applied, fmt.Errorf("mongoschema: record migration %s : %w", mg.Name, err)
+		}
+
+		applied = append(applied, mg)
+	}
+
+	return applied, nil
+}
+
+// Status returns copies of all declared migrations in order of name,
+// applied ones have AppliedAt
+func (m *Migrator) Status(ctx context.Context) ([]*Migration, error) {
+	cur, err := m.db.Collection(m.collection).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("mongoschema: list migrations : %w", err)
+	}
+
+	var records []Migration
+	err = cur.All(ctx, &records)
+	if err != nil {
+		return nil, fmt.Errorf("mongoschema: list migrations : %w", err)
+	}
+
+	appliedAt := make(map[string]time.Time, len(records))
+	for _, rec := range records {
+		appliedAt[rec.Name] = rec.AppliedAt
+	}
+
+	all := make([]*Migration, 0, len(m.schema.migrations))
+	for _, mg := range m.schema.migrations {
+		all = append(all, &Migration{Name: mg.Name, Up: mg.Up, AppliedAt: appliedAt[mg.Name]})
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+
+	return all, nil
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,287 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file mongoschema_test.go
+ * @package mongoschema
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package mongoschema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIndexName(t *testing.T) {
+	tests := []struct {
+		idx  Index
+		want string
+	}{
+		{Index{Keys: bson.D{{Key: "status", Value: 1}}}, "status_1"},
+		{Index{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}}, "status_1_created_at_-1"},
+		{Index{Keys: bson.D{{Key: "title", Value: "text"}}}, "title_text"},
+		{Index{Name: "by_status", Keys: bson.D{{Key: "status", Value: 1}}}, "by_status"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.idx.name(); got != tt.want {
+			t.Errorf("name of %v = %q, want %q", tt.idx.Keys, got, tt.want)
+		}
+	}
+}
+
+func TestSame(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y any
+		want bool
+	}{
+		{"int and int32", 1, int32(1), true},
+		{"int and double", -1, float64(-1), true},
+		{"int64 and int32", int64(3600), int32(3600), true},
+		{"different numbers", 1, -1, false},
+		{"map key order", bson.M{"a": 1, "b": "x"}, bson.D{{Key: "b", Value: "x"}, {Key: "a", Value: int32(1)}}, true},
+		{"nested", bson.M{"status": bson.M{"$exists": true}}, bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: true}}}}, true},
+		{"nested differs", bson.M{"status": bson.M{"$exists": true}}, bson.M{"status": bson.M{"$exists": false}}, false},
+		{"string and number", "1", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := same(tt.x, tt.y); got != tt.want {
+				t.Errorf("same(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+
+	if canonical(make(chan int)) != nil {
+		t.Error("unmarshalable value not nil")
+	}
+}
+
+// spec decodes an index document as listed by server
+func spec(t *testing.T, doc bson.D) *indexSpec {
+	t.Helper()
+
+	src, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &indexSpec{}
+	err = bson.Unmarshal(src, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestIndexDrift(t *testing.T) {
+	keys := bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}
+	serverKeys := bson.D{{Key: "status", Value: int32(1)}, {Key: "created_at", Value: int32(-1)}}
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: true}}}}
+
+	tests := []struct {
+		name string
+		idx  Index
+		spec bson.D
+		want string
+	}{
+		{
+			name: "same",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "name", Value: "status_1_created_at_-1"}, {Key: "key", Value: serverKeys}},
+		},
+		{
+			name: "double keys",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: bson.D{{Key: "status", Value: 1.0}, {Key: "created_at", Value: -1.0}}}},
+		},
+		{
+			name: "key order",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: bson.D{{Key: "created_at", Value: int32(-1)}, {Key: "status", Value: int32(1)}}}},
+			want: "keys differ",
+		},
+		{
+			name: "direction",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: bson.D{{Key: "status", Value: int32(1)}, {Key: "created_at", Value: int32(1)}}}},
+			want: "keys differ",
+		},
+		{
+			name: "fewer keys",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: bson.D{{Key: "status", Value: int32(1)}}}},
+			want: "keys differ",
+		},
+		{
+			name: "unique",
+			idx:  Index{Keys: keys, Unique: true},
+			spec: bson.D{{Key: "key", Value: serverKeys}},
+			want: "unique is false",
+		},
+		{
+			name: "sparse",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "sparse", Value: true}},
+			want: "sparse is true",
+		},
+		{
+			name: "ttl",
+			idx:  Index{Keys: keys, ExpireAfter: time.Hour},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "expireAfterSeconds", Value: int32(3600)}},
+		},
+		{
+			name: "ttl missing",
+			idx:  Index{Keys: keys, ExpireAfter: time.Hour},
+			spec: bson.D{{Key: "key", Value: serverKeys}},
+			want: "not a TTL index",
+		},
+		{
+			name: "ttl differs",
+			idx:  Index{Keys: keys, ExpireAfter: time.Hour},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "expireAfterSeconds", Value: int64(60)}},
+			want: "expires after 60s",
+		},
+		{
+			name: "ttl not declared",
+			idx:  Index{Keys: keys},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "expireAfterSeconds", Value: int32(60)}},
+			want: "expires after 60s",
+		},
+		{
+			name: "partial filter",
+			idx:  Index{Keys: keys, PartialFilter: filter},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "partialFilterExpression", Value: filter}},
+		},
+		{
+			name: "partial filter missing",
+			idx:  Index{Keys: keys, PartialFilter: filter},
+			spec: bson.D{{Key: "key", Value: serverKeys}},
+			want: "partial filter differs",
+		},
+		{
+			name: "partial filter differs",
+			idx:  Index{Keys: keys, PartialFilter: filter},
+			spec: bson.D{{Key: "key", Value: serverKeys}, {Key: "partialFilterExpression", Value: bson.D{{Key: "status", Value: "a"}}}},
+			want: "partial filter differs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indexDrift(&tt.idx, spec(t, tt.spec)); got != tt.want {
+				t.Errorf("drift %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorDrift(t *testing.T) {
+	schema := bson.M{"bsonType": "object", "required": bson.A{"status"}}
+	serverSchema := bson.D{{Key: "required", Value: bson.A{"status"}}, {Key: "bsonType", Value: "object"}}
+
+	options := func(doc bson.D) bson.Raw {
+		if doc == nil {
+			return nil
+		}
+
+		src, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return src
+	}
+
+	tests := []struct {
+		name string
+		coll Collection
+		opts bson.D
+		want []string
+	}{
+		{
+			name: "none",
+			coll: Collection{Name: "c"},
+		},
+		{
+			name: "same",
+			coll: Collection{Name: "c", JSONSchema: schema},
+			opts: bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: serverSchema}}}},
+		},
+		{
+			name: "server defaults",
+			coll: Collection{Name: "c", JSONSchema: schema, ValidationLevel: "strict", ValidationAction: "error"},
+			opts: bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: serverSchema}}}},
+		},
+		{
+			name: "not declared",
+			coll: Collection{Name: "c"},
+			opts: bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: serverSchema}}}},
+			want: []string{"validator not declared"},
+		},
+		{
+			name: "missing",
+			coll: Collection{Name: "c", JSONSchema: schema},
+			want: []string{"validator missing"},
+		},
+		{
+			name: "query validator",
+			coll: Collection{Name: "c", JSONSchema: schema},
+			opts: bson.D{{Key: "validator", Value: bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: true}}}}}},
+			want: []string{"validator missing"},
+		},
+		{
+			name: "differs",
+			coll: Collection{Name: "c", JSONSchema: schema},
+			opts: bson.D{{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: bson.D{{Key: "bsonType", Value: "object"}}}}}},
+			want: []string{"validator differs"},
+		},
+		{
+			name: "level and action",
+			coll: Collection{Name: "c", JSONSchema: schema, ValidationLevel: "Strict", ValidationAction: "error"},
+			opts: bson.D{
+				{Key: "validator", Value: bson.D{{Key: "$jsonSchema", Value: serverSchema}}},
+				{Key: "validationLevel", Value: "moderate"},
+				{Key: "validationAction", Value: "warn"},
+			},
+			want: []string{"validation level is moderate", "validation action is warn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drifts := validatorDrift(&tt.coll, &mongo.CollectionSpecification{Name: "c", Options: options(tt.opts)})
+
+			var got []string
+			for _, d := range drifts {
+				got = append(got, d.Reason)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("drifts %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file audit.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"strings"
+
+	"github.com/zenkoo-live/svc.base/audit"
+)
+
+const (
+	AuditSinkDatabase = "database"
+	AuditSinkBroker   = "broker"
+)
+
+// auditHook records changes of audit.Auditable models. Failures are logged
+// and never fail the query, strict ones also fail transaction of RunInTx.
+func (r *Runtime) auditHook(cfg *configAudit) *audit.Hook {
+	sink := audit.DatabaseSink(cfg.Table)
+	if strings.ToLower(cfg.Sink) == AuditSinkBroker {
+		sink = audit.BrokerSink(r.Broker, cfg.Topic)
+	}
+
+	var opts []audit.HookOption
+	if cfg.Strict {
+		opts = append(opts, audit.Strict())
+	}
+
+	return audit.NewHook(sink, func(err error) {
+		r.log.Error(err.Error())
+	}, opts...)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file audit_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"go-micro.dev/v4/broker"
+)
+
+type testAuditItem struct {
+	bun.BaseModel `bun:"table:items"`
+
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+func (*testAuditItem) AuditSkip() []string {
+	return nil
+}
+
+func TestRunInTxStrictAudit(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		// No audit table, records fail
+		r := testRuntimeDB(t, &Config{})
+		r.db.AddQueryHook(r.auditHook(&configAudit{Strict: strict}))
+		ctx := context.Background()
+
+		err := r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			_, err := r.IDB(ctx).NewInsert().Model(&testAuditItem{Name: "a"}).Exec(ctx)
+
+			return err
+		})
+		if (err != nil) != strict {
+			t.Errorf("strict %v: transaction returned %v", strict, err)
+		}
+
+		want := 1
+		if strict {
+			want = 0
+		}
+
+		if n := countItems(t, r.db); n != want {
+			t.Errorf("strict %v: %d rows, want %d", strict, n, want)
+		}
+	}
+}
+
+func TestRunInTxBrokerAudit(t *testing.T) {
+	r := testRuntimeDB(t, &Config{})
+	r.brk = broker.NewMemoryBroker()
+	err := r.brk.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var published []string
+	_, err = r.brk.Subscribe("audit", func(e broker.Event) error {
+		published = append(published, string(e.Message().Body))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.db.AddQueryHook(r.auditHook(&configAudit{Sink: AuditSinkBroker}))
+	ctx := context.Background()
+	insert := func(ctx context.Context, name string) error {
+		_, err := r.IDB(ctx).NewInsert().Model(&testAuditItem{Name: name}).Exec(ctx)
+
+		return err
+	}
+
+	// Rolled back
+	err = r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := insert(ctx, "a")
+		if err != nil {
+			return err
+		}
+
+		if len(published) > 0 {
+			t.Error("published before commit")
+		}
+
+		return errors.New("rollback")
+	})
+	if err == nil || len(published) > 0 {
+		t.Errorf("rolled back transaction: %v, published %d", err, len(published))
+	}
+
+	// Committed, with a rolled back savepoint
+	err = r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		err := insert(ctx, "b")
+		if err != nil {
+			return err
+		}
+
+		r.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			err := insert(ctx, "c")
+			if err != nil {
+				return err
+			}
+
+			return errors.New("rollback savepoint")
+		})
+
+		return r.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			return insert(ctx, "d")
+		})
+	})
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	if len(published) != 2 {
+		t.Errorf("published %d records, want 2 of b and d", len(published))
+	}
+
+	// Outside of transaction
+	published = nil
+	err = insert(ctx, "e")
+	if err != nil || len(published) != 1 {
+		t.Errorf("autocommit insert: %v, published %d", err, len(published))
+	}
+
+	// Transaction not run by RunInTx is refused
+	published = nil
+	err = r.db.RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().Model(&testAuditItem{Name: "f"}).Exec(ctx)
+
+		return err
+	})
+	if err != nil || len(published) > 0 {
+		t.Errorf("plain transaction: %v, published %d", err, len(published))
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -14,6 +14,8 @@
 package runtime
 
 import (
+	"encoding/json"
+
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/mattn/go-sqlite3"
@@ -28,6 +30,8 @@ const (
 	DefaultRegistryDriver = "consul"
 	DefaultBrokerDriver   = "nats"
 	DefaultCacheDriver    = "redis"
+	DefaultStoreDriver    = "memory"
+	DefaultDatabaseDriver = "postgres"
 
 	DefaultHTTPAdvertiseAddr = ":9990"
 	DefaultGRPCAdvertiseAddr = ":9991"
@@ -39,29 +43,57 @@ type configRegistry struct {
 }
 
 type configBroker struct {
-	Driver  string   `json:"driver" mapstructure:"driver"`
-	Address []string `json:"address" mapstructure:"address"`
+	Driver  string       `json:"driver" mapstructure:"driver"`
+	Address []string     `json:"address" mapstructure:"address"`
+	Retry   *configRetry `json:"retry" mapstructure:"retry"`
 }
 
 type configCache struct {
-	Driver   string   `json:"driver" mapstructure:"driver"`
-	Address  []string `json:"address" mapstructure:"address"`
-	Password string   `json:"password" mapstructure:"password"`
-	DB       int      `json:"db" mapstructure:"db"`
+	Driver     string   `json:"driver" mapstructure:"driver"`
+	Address    []string `json:"address" mapstructure:"address"`
+	MasterName string   `json:"master_name" mapstructure:"master_name"`
+	Password   Secret   `json:"password" mapstructure:"password"`
+	DB         int      `json:"db" mapstructure:"db"`
 }
 
 type configStore struct {
-	Driver   string   `json:"driver" mapstructure:"driver"`
-	Address  []string `json:"address" mapstructure:"address"`
-	Password string   `json:"password" mapstructure:"password"`
-	DB       int      `json:"db" mapstructure:"db"`
+	Driver     string   `json:"driver" mapstructure:"driver"`
+	Address    []string `json:"address" mapstructure:"address"`
+	MasterName string   `json:"master_name" mapstructure:"master_name"`
+	Password   Secret   `json:"password" mapstructure:"password"`
+	DB         int      `json:"db" mapstructure:"db"`
 }
 
 type configDatabase struct {
 	Driver            string `json:"driver" mapstructure:"driver"`
-	DSN               string `json:"dsn" mapstructure:"dsn"`
+	DSN               Secret `json:"dsn" mapstructure:"dsn"`
 	Debug             bool   `json:"debug" mapstructure:"debug"`
 	SlowQueryDuration int    `json:"slow_query_duration" mapstructure:"slow_query_duration"`
+
+	// Pool, lifetime and idle time in seconds, zero keeps database/sql default
+	MaxOpenConns    int `json:"max_open_conns" mapstructure:"max_open_conns"`
+	MaxIdleConns    int `json:"max_idle_conns" mapstructure:"max_idle_conns"`
+	ConnMaxLifetime int `json:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime int `json:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
+
+	// Driver timeouts in milliseconds, for postgres and mysql
+	DialTimeout  int `json:"dial_timeout" mapstructure:"dial_timeout"`
+	ReadTimeout  int `json:"read_timeout" mapstructure:"read_timeout"`
+	WriteTimeout int `json:"write_timeout" mapstructure:"write_timeout"`
+
+	// Read replicas, share driver and pool settings with primary
+	Replicas []Secret `json:"replicas" mapstructure:"replicas"`
+	// round_robin (default) or least_conn
+	ReplicaPolicy string `json:"replica_policy" mapstructure:"replica_policy"`
+	// Milliseconds, postgres only, zero disables lag check
+	ReplicaMaxLag int `json:"replica_max_lag" mapstructure:"replica_max_lag"`
+	// Seconds
+	ReplicaCheckInterval int `json:"replica_check_interval" mapstructure:"replica_check_interval"`
+
+	Retry   *configRetry   `json:"retry" mapstructure:"retry"`
+	Migrate *configMigrate `json:"migrate" mapstructure:"migrate"`
+	// RunInTx retries on deadlock or serialization failure, mode is ignored
+	TxRetry *configRetry `json:"tx_retry" mapstructure:"tx_retry"`
 }
 
 type configFiber struct {
@@ -76,13 +108,59 @@ type configFiber struct {
 	DisableKeepAlive bool   `json:"disable_keep_alive" mapstructure:"disable_keep_alive"`
 	EnableSwagger    bool   `json:"enable_swagger" mapstructure:"enable_swagger"`
 	EnableStackTrace bool   `json:"enable_stack_trace" mapstructure:"enable_stack_trace"`
+	// Mount /healthz and /readyz
+	EnableHealthCheck  bool `json:"enable_health_check" mapstructure:"enable_health_check"`
+	HealthCheckTimeout int  `json:"health_check_timeout" mapstructure:"health_check_timeout"`
+
+	Cors *configCors `json:"cors" mapstructure:"cors"`
+	// Per request query accounting, handlers must query with c.UserContext()
+	QueryStats *configQueryStats `json:"query_stats" mapstructure:"query_stats"`
+}
+
+type configCors struct {
+	AllowOrigins     string `json:"allow_origins" mapstructure:"allow_origins"`
+	AllowMethods     string `json:"allow_methods" mapstructure:"allow_methods"`
+	AllowHeaders     string `json:"allow_headers" mapstructure:"allow_headers"`
+	AllowCredentials bool   `json:"allow_credentials" mapstructure:"allow_credentials"`
+	ExposeHeaders    string `json:"expose_headers" mapstructure:"expose_headers"`
+	MaxAge           int    `json:"max_age" mapstructure:"max_age"`
+}
+
+type configQueryStats struct {
+	// Warn if a request runs more queries, zero disables
+	MaxQueries int `json:"max_queries" mapstructure:"max_queries"`
+	// Warn if a query shape repeats more times in a request, zero disables
+	MaxRepeats int `json:"max_repeats" mapstructure:"max_repeats"`
 }
 
 type configSession struct {
+	// cookie (default) or header, other values are taken as header
 	IDSource   string `json:"id_source" mapstructure:"id_source"`
 	IDKey      string `json:"id_key" mapstructure:"id_key"`
 	IDPrefix   string `json:"id_prefix" mapstructure:"id_prefix"`
 	Expiration int    `json:"expiration" mapstructure:"expiration"`
+	// Session data key of user ID, see defs.Entity
+	ActorKey string `json:"actor_key" mapstructure:"actor_key"`
+}
+
+type configTenant struct {
+	// session (default) or header, header is only for services behind a
+	// gateway which sets it, platform merchant is rejected from header
+	Source string `json:"source" mapstructure:"source"`
+	// Session data key or header name
+	Key    string `json:"key" mapstructure:"key"`
+	Strict bool   `json:"strict" mapstructure:"strict"`
+}
+
+type configAudit struct {
+	// database (default) writes in the same transaction, broker publishes
+	// after commit of RunInTx and drops records of other transactions
+	Sink  string `json:"sink" mapstructure:"sink"`
+	Table string `json:"table" mapstructure:"table"`
+	Topic string `json:"topic" mapstructure:"topic"`
+	// Roll back transaction of RunInTx if records are not stored, queries
+	// outside of it only log the failure
+	Strict bool `json:"strict" mapstructure:"strict"`
 }
 
 type configLogger struct {
@@ -93,17 +171,100 @@ type configLogger struct {
 }
 
 type configMongo struct {
-	DSN string `json:"dsn" mapstructure:"dsn"`
+	DSN Secret `json:"dsn" mapstructure:"dsn"`
+	// Database of MongoDB(), database in DSN if empty
+	Database string `json:"database" mapstructure:"database"`
+	// Log every command, or only commands slower than milliseconds
+	Debug               bool `json:"debug" mapstructure:"debug"`
+	SlowCommandDuration int  `json:"slow_command_duration" mapstructure:"slow_command_duration"`
+
+	// Zero keeps driver default
+	MaxPoolSize int `json:"max_pool_size" mapstructure:"max_pool_size"`
+	MinPoolSize int `json:"min_pool_size" mapstructure:"min_pool_size"`
+
+	// Milliseconds, zero keeps driver default
+	ConnectTimeout         int `json:"connect_timeout" mapstructure:"connect_timeout"`
+	ServerSelectionTimeout int `json:"server_selection_timeout" mapstructure:"server_selection_timeout"`
+
+	// primary, primaryPreferred, secondary, secondaryPreferred or nearest
+	ReadPreference string `json:"read_preference" mapstructure:"read_preference"`
+	// majority or number of acknowledging nodes
+	WriteConcern string `json:"write_concern" mapstructure:"write_concern"`
+
+	Retry *configRetry `json:"retry" mapstructure:"retry"`
+	// MongoTx replays transactions failed with transient errors, mode is
+	// ignored
+	TxRetry *configRetry `json:"tx_retry" mapstructure:"tx_retry"`
+	// Reconcile and migrate schema registered by RegisterMongoSchema,
+	// tables are collections
+	Migrate *configMigrate `json:"migrate" mapstructure:"migrate"`
+}
+
+// Addresses is a list of addresses, also decoded from a single string as
+// configs written for one address
+type Addresses []string
+
+func (a *Addresses) UnmarshalJSON(b []byte) error {
+	var addr string
+	if json.Unmarshal(b, &addr) == nil {
+		*a = nil
+		if addr != "" {
+			*a = Addresses{addr}
+		}
+
+		return nil
+	}
+
+	return json.Unmarshal(b, (*[]string)(a))
 }
 
 type configRedis struct {
-	Address    string `json:"address" mapstructure:"address"`
-	Password   string `json:"password" mapstructure:"password"`
-	PoolSize   int    `json:"pool_size" mapstructure:"pool_size"`
-	MaxRetries int    `json:"max_retries" mapstructure:"max_retries"`
-	DB         int    `json:"db" mapstructure:"db"`
+	// One address for single node, more for cluster, or sentinels if
+	// master name set
+	Address    Addresses `json:"address" mapstructure:"address"`
+	MasterName string    `json:"master_name" mapstructure:"master_name"`
+	// Cluster only, route read only commands to the closest node
+	RouteByLatency bool   `json:"route_by_latency" mapstructure:"route_by_latency"`
+	Password       Secret `json:"password" mapstructure:"password"`
+	PoolSize       int    `json:"pool_size" mapstructure:"pool_size"`
+	MaxRetries     int    `json:"max_retries" mapstructure:"max_retries"`
+	DB             int    `json:"db" mapstructure:"db"`
+
+	Retry *configRetry `json:"retry" mapstructure:"retry"`
+}
+
+type configMigrate struct {
+	// Apply pending migrations registered by RegisterMigrations at startup
+	Auto       bool   `json:"auto" mapstructure:"auto"`
+	Table      string `json:"table" mapstructure:"table"`
+	LocksTable string `json:"locks_table" mapstructure:"locks_table"`
+	// Milliseconds to wait for another instance holding the lock
+	LockTimeout int `json:"lock_timeout" mapstructure:"lock_timeout"`
+	// Milliseconds before lock of a crashed instance is taken over
+	LockTTL int `json:"lock_ttl" mapstructure:"lock_ttl"`
+}
+
+type configRetry struct {
+	// Total attempts at startup, includes the first one
+	Attempts int `json:"attempts" mapstructure:"attempts"`
+	// Milliseconds
+	InitialDelay int `json:"initial_delay" mapstructure:"initial_delay"`
+	MaxDelay     int `json:"max_delay" mapstructure:"max_delay"`
+	// Randomize delay by +/- fraction, 0 - 1
+	Jitter float64 `json:"jitter" mapstructure:"jitter"`
+	// fail_fast (default) or degraded
+	Mode string `json:"mode" mapstructure:"mode"`
 }
 
+// Exported aliases of config sections, used by driver factories
+type (
+	RegistryConfig = configRegistry
+	BrokerConfig   = configBroker
+	CacheConfig    = configCache
+	StoreConfig    = configStore
+	DatabaseConfig = configDatabase
+)
+
 type Config struct {
 	Registry *configRegistry `json:"registry" mapstructure:"registry"`
 	Broker   *configBroker   `json:"broker" mapstructure:"broker"`
@@ -114,7 +275,12 @@ type Config struct {
 	Redis    *configRedis    `json:"redis" mapstructure:"redis"`
 	Fiber    *configFiber    `json:"fiber" mapstructure:"fiber"`
 	Session  *configSession  `json:"session" mapstructure:"session"`
+	Tenant   *configTenant   `json:"tenant" mapstructure:"tenant"`
+	Audit    *configAudit    `json:"audit" mapstructure:"audit"`
 	Logger   *configLogger   `json:"logger" mapstructure:"logger"`
+
+	// Named databases, see DBNamed
+	Databases map[string]*configDatabase `json:"databases" mapstructure:"databases"`
 }
 
 /*
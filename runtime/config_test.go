@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file config_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testConfig(t *testing.T, src string) *Config {
+	t.Helper()
+
+	cfg := &Config{}
+	err := json.Unmarshal([]byte(src), cfg)
+	if err != nil {
+		t.Fatalf("decode config: %s", err)
+	}
+
+	return cfg
+}
+
+func TestAddresses(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{`{"redis": {"address": "a:6379"}}`, []string{"a:6379"}},
+		{`{"redis": {"address": ["a:6379", "b:6379"]}}`, []string{"a:6379", "b:6379"}},
+		{`{"redis": {"address": ""}}`, nil},
+		{`{"redis": {"address": null}}`, nil},
+		{`{"redis": {}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got := testConfig(t, tt.src).Redis.Address
+			if !reflect.DeepEqual([]string(got), tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	cfg := &Config{}
+	err := json.Unmarshal([]byte(`{"redis": {"address": 6379}}`), cfg)
+	if err == nil {
+		t.Error("number accepted as address")
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file database.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// DefaultDatabaseName refers to DB(), also used when only databases
+	// section is configured
+	DefaultDatabaseName = "main"
+)
+
+// database is one connection with its own hooks and replicas
+type database struct {
+	db       *bun.DB
+	queryLog *queryLogHook
+	replicas *replicaSet
+}
+
+func (d *database) close() error {
+	var errs error
+	if d.replicas != nil {
+		errs = d.replicas.close()
+	}
+
+	return errors.Join(errs, d.db.Close())
+}
+
+func (r *Runtime) initDatabases(cfgs map[string]*configDatabase) error {
+	var errs error
+
+	names := make([]string, 0, len(cfgs))
+	for name := range cfgs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	r.dbs = make(map[string]*database, len(cfgs))
+	for _, name := range names {
+		d, err := r.initDatabase(name, cfgs[name])
+		if err != nil {
+			errs = errors.Join(errs, err)
+
+			continue
+		}
+
+		r.dbs[name] = d
+	}
+
+	// Default entry
+	if r.db == nil {
+		if d, ok := r.dbs[DefaultDatabaseName]; ok {
+			r.db = d.db
+			r.replicas = d.replicas
+		}
+	}
+
+	return errs
+}
+
+func (r *Runtime) closeDatabases() error {
+	var errs error
+	for _, d := range r.dbs {
+		errs = errors.Join(errs, d.close())
+	}
+
+	r.dbs = nil
+
+	return errs
+}
+
+// DBNamed returns database configured in databases section, DefaultDatabaseName
+// falls back to DB(). Returns nil if not configured.
+func (r *Runtime) DBNamed(name string) *bun.DB {
+	if d, ok := r.dbs[name]; ok {
+		return d.db
+	}
+
+	if name == DefaultDatabaseName {
+		return r.db
+	}
+
+	return nil
+}
+
+// DBReadNamed returns a healthy read replica of database name, falls back
+// to its primary. DefaultDatabaseName falls back to DBRead(). Returns nil if
+// not configured.
+func (r *Runtime) DBReadNamed(name string) *bun.DB {
+	if d, ok := r.dbs[name]; ok {
+		if d.replicas == nil {
+			return d.db
+		}
+
+		return d.replicas.pick()
+	}
+
+	if name == DefaultDatabaseName {
+		return r.DBRead()
+	}
+
+	return nil
+}
+
+// DBStatsNamed returns pool statistics of DBNamed, zero if not configured
+func (r *Runtime) DBStatsNamed(name string) sql.DBStats {
+	db := r.DBNamed(name)
+	if db == nil {
+		return sql.DBStats{}
+	}
+
+	return db.Stats()
+}
+
+// databaseConfig returns config of database name, as DBNamed resolves it
+func (r *Runtime) databaseConfig(name string) *configDatabase {
+	if c, ok := r.cfg.Databases[name]; ok {
+		return c
+	}
+
+	if name == DefaultDatabaseName {
+		return r.cfg.Database
+	}
+
+	return nil
+}
+
+func DBNamed(name string) *bun.DB {
+	return defaultRuntime.DBNamed(name)
+}
+
+func DBReadNamed(name string) *bun.DB {
+	return defaultRuntime.DBReadNamed(name)
+}
+
+func DBStatsNamed(name string) sql.DBStats {
+	return defaultRuntime.DBStatsNamed(name)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
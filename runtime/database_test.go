@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file database_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+)
+
+func TestDBReadNamed(t *testing.T) {
+	// Never connected, only compared
+	open := func() *bun.DB {
+		sqldb, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return bun.NewDB(sqldb, sqlitedialect.New())
+	}
+
+	main, primary, standby, plain := open(), open(), open(), open()
+	rp := &replica{db: standby}
+	r := &Runtime{
+		db: main,
+		dbs: map[string]*database{
+			"legacy":  {db: primary, replicas: &replicaSet{primary: primary, replicas: []*replica{rp}}},
+			"reports": {db: plain},
+		},
+	}
+
+	if db := r.DBReadNamed("legacy"); db != primary {
+		t.Error("unhealthy replica picked")
+	}
+
+	rp.healthy.Store(true)
+	if db := r.DBReadNamed("legacy"); db != standby {
+		t.Error("healthy replica not picked")
+	}
+
+	if db := r.DBReadNamed("reports"); db != plain {
+		t.Error("database without replicas does not read primary")
+	}
+
+	if db := r.DBReadNamed(DefaultDatabaseName); db != main {
+		t.Error("default database does not fall back to DBRead")
+	}
+
+	if db := r.DBReadNamed("missing"); db != nil {
+		t.Error("unknown database returned")
+	}
+
+	if s := r.DBStatsNamed("missing"); s.MaxOpenConnections != 0 {
+		t.Error("stats of unknown database")
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
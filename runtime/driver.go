@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file driver.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	brkKafka "github.com/go-micro/plugins/v4/broker/kafka"
+	brkNats "github.com/go-micro/plugins/v4/broker/nats"
+	brkRabbitmq "github.com/go-micro/plugins/v4/broker/rabbitmq"
+	chRedis "github.com/go-micro/plugins/v4/cache/redis"
+	rgConsul "github.com/go-micro/plugins/v4/registry/consul"
+	rgEtcd "github.com/go-micro/plugins/v4/registry/etcd"
+	stConsul "github.com/go-micro/plugins/v4/store/consul"
+	stRedis "github.com/go-micro/plugins/v4/store/redis"
+	redis8 "github.com/go-redis/redis/v8"
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mssqldialect"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"go-micro.dev/v4/broker"
+	"go-micro.dev/v4/cache"
+	"go-micro.dev/v4/registry"
+	"go-micro.dev/v4/store"
+)
+
+type (
+	RegistryFactory func(cfg *RegistryConfig) (registry.Registry, error)
+	BrokerFactory   func(cfg *BrokerConfig) (broker.Broker, error)
+	CacheFactory    func(cfg *CacheConfig) (cache.Cache, error)
+	StoreFactory    func(cfg *StoreConfig) (store.Store, error)
+	// DatabaseFactory opens the connection only, ping and hooks are applied by runtime
+	DatabaseFactory func(cfg *DatabaseConfig) (*bun.DB, error)
+)
+
+type driverSet[F any] struct {
+	sync.RWMutex
+	kind      string
+	def       string
+	factories map[string]F
+}
+
+func newDriverSet[F any](kind, def string) *driverSet[F] {
+	return &driverSet[F]{
+		kind:      kind,
+		def:       def,
+		factories: make(map[string]F),
+	}
+}
+
+func (s *driverSet[F]) register(name string, factory F) {
+	s.Lock()
+	s.factories[strings.ToLower(name)] = factory
+	s.Unlock()
+}
+
+func (s *driverSet[F]) get(name string) (F, error) {
+	name = strings.ToLower(name)
+	if name == "" {
+		name = s.def
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	factory, ok := s.factories[name]
+	if !ok {
+		return factory, fmt.Errorf("unknown %s driver <%s>, registered: %s", s.kind, name, strings.Join(s.names(), ", "))
+	}
+
+	return factory, nil
+}
+
+func (s *driverSet[F]) names() []string {
+	names := make([]string, 0, len(s.factories))
+	for name := range s.factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+var (
+	registryDrivers = newDriverSet[RegistryFactory]("registry", DefaultRegistryDriver)
+	brokerDrivers   = newDriverSet[BrokerFactory]("broker", DefaultBrokerDriver)
+	cacheDrivers    = newDriverSet[CacheFactory]("cache", DefaultCacheDriver)
+	storeDrivers    = newDriverSet[StoreFactory]("store", DefaultStoreDriver)
+	databaseDrivers = newDriverSet[DatabaseFactory]("database", DefaultDatabaseDriver)
+)
+
+func RegisterRegistryDriver(name string, factory RegistryFactory) {
+	registryDrivers.register(name, factory)
+}
+
+func RegisterBrokerDriver(name string, factory BrokerFactory) {
+	brokerDrivers.register(name, factory)
+}
+
+func RegisterCacheDriver(name string, factory CacheFactory) {
+	cacheDrivers.register(name, factory)
+}
+
+func RegisterStoreDriver(name string, factory StoreFactory) {
+	storeDrivers.register(name, factory)
+}
+
+func RegisterDatabaseDriver(name string, factory DatabaseFactory) {
+	databaseDrivers.register(name, factory)
+}
+
+func init() {
+	// Registry
+	RegisterRegistryDriver("consul", func(cfg *RegistryConfig) (registry.Registry, error) {
+		return rgConsul.NewRegistry(registry.Addrs(cfg.Address...)), nil
+	})
+	RegisterRegistryDriver("etcd", func(cfg *RegistryConfig) (registry.Registry, error) {
+		// ETCD v3
+		return rgEtcd.NewRegistry(registry.Addrs(cfg.Address...)), nil
+	})
+
+	// Broker
+	RegisterBrokerDriver("nats", func(cfg *BrokerConfig) (broker.Broker, error) {
+		return brkNats.NewBroker(broker.Addrs(cfg.Address...)), nil
+	})
+	RegisterBrokerDriver("kafka", func(cfg *BrokerConfig) (broker.Broker, error) {
+		return brkKafka.NewBroker(broker.Addrs(cfg.Address...)), nil
+	})
+	RegisterBrokerDriver("rabbitmq", func(cfg *BrokerConfig) (broker.Broker, error) {
+		return brkRabbitmq.NewBroker(broker.Addrs(cfg.Address...)), nil
+	})
+
+	// Cache
+	RegisterCacheDriver("memory", func(cfg *CacheConfig) (cache.Cache, error) {
+		return cache.DefaultCache, nil
+	})
+	RegisterCacheDriver("redis", func(cfg *CacheConfig) (cache.Cache, error) {
+		return chRedis.NewCache(
+			chRedis.WithRedisOptions(
+				redis8.UniversalOptions{
+					Addrs:      cfg.Address,
+					MasterName: cfg.MasterName,
+					DB:         cfg.DB,
+					Password:   cfg.Password.Value(),
+				},
+			),
+		), nil
+	})
+
+	// Store
+	RegisterStoreDriver("memory", func(cfg *StoreConfig) (store.Store, error) {
+		return store.DefaultStore, nil
+	})
+	RegisterStoreDriver("consul", func(cfg *StoreConfig) (store.Store, error) {
+		// TODO: DO NOT USE ME
+		return stConsul.NewStore(), nil
+	})
+	RegisterStoreDriver("redis", func(cfg *StoreConfig) (store.Store, error) {
+		return stRedis.NewStore(
+			stRedis.WithRedisOptions(
+				redis8.UniversalOptions{
+					Addrs:      cfg.Address,
+					MasterName: cfg.MasterName,
+					DB:         cfg.DB,
+					Password:   cfg.Password.Value(),
+				},
+			),
+		), nil
+	})
+
+	// Database
+	postgres := func(cfg *DatabaseConfig) (*bun.DB, error) {
+		opts := []pgdriver.Option{
+			pgdriver.WithDSN(cfg.DSN.Value()),
+		}
+		if cfg.DialTimeout > 0 {
+			opts = append(opts, pgdriver.WithDialTimeout(time.Duration(cfg.DialTimeout)*time.Millisecond))
+		}
+
+		if cfg.ReadTimeout > 0 {
+			opts = append(opts, pgdriver.WithReadTimeout(time.Duration(cfg.ReadTimeout)*time.Millisecond))
+		}
+
+		if cfg.WriteTimeout > 0 {
+			opts = append(opts, pgdriver.WithWriteTimeout(time.Duration(cfg.WriteTimeout)*time.Millisecond))
+		}
+
+		sqldb := sql.OpenDB(pgdriver.NewConnector(opts...))
+
+		return bun.NewDB(sqldb, pgdialect.New()), nil
+	}
+	RegisterDatabaseDriver("postgres", postgres)
+	RegisterDatabaseDriver("postgresql", postgres)
+	RegisterDatabaseDriver("mysql", func(cfg *DatabaseConfig) (*bun.DB, error) {
+		mc, err := mysql.ParseDSN(cfg.DSN.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.DialTimeout > 0 {
+			mc.Timeout = time.Duration(cfg.DialTimeout) * time.Millisecond
+		}
+
+		if cfg.ReadTimeout > 0 {
+			mc.ReadTimeout = time.Duration(cfg.ReadTimeout) * time.Millisecond
+		}
+
+		if cfg.WriteTimeout > 0 {
+			mc.WriteTimeout = time.Duration(cfg.WriteTimeout) * time.Millisecond
+		}
+
+		sqldb, err := sql.Open("mysql", mc.FormatDSN())
+		if err != nil {
+			return nil, err
+		}
+
+		return bun.NewDB(sqldb, mysqldialect.New()), nil
+	})
+	RegisterDatabaseDriver("mssql", func(cfg *DatabaseConfig) (*bun.DB, error) {
+		// MS-SQLServer
+		sqldb, err := sql.Open("sqlserver", cfg.DSN.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return bun.NewDB(sqldb, mssqldialect.New()), nil
+	})
+	RegisterDatabaseDriver("sqlite", func(cfg *DatabaseConfig) (*bun.DB, error) {
+		sqldb, err := sql.Open("sqlite3", cfg.DSN.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return bun.NewDB(sqldb, sqlitedialect.New()), nil
+	})
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,330 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file graph.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type component struct {
+	name    string
+	enabled func(cfg *Config) bool
+	deps    func(cfg *Config) []string
+	init    func(r *Runtime) error
+	close   func(ctx context.Context, r *Runtime) error
+}
+
+// Declaration order is kept for components without dependency between them
+var components = []*component{
+	{
+		name:    "registry",
+		enabled: func(cfg *Config) bool { return cfg.Registry != nil },
+		init: func(r *Runtime) (err error) {
+			r.rg, err = r.initRegistry(r.cfg.Registry)
+
+			return
+		},
+	},
+	{
+		name:    "broker",
+		enabled: func(cfg *Config) bool { return cfg.Broker != nil },
+		init: func(r *Runtime) (err error) {
+			r.brk, err = r.initBroker(r.cfg.Broker)
+
+			return
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			if !r.brkConnected.Swap(false) {
+				return nil
+			}
+
+			return r.brk.Disconnect()
+		},
+	},
+	{
+		name:    "cache",
+		enabled: func(cfg *Config) bool { return cfg.Cache != nil },
+		deps: func(cfg *Config) []string {
+			if sharesRedis(cfg.Cache.Driver, DefaultCacheDriver, cfg.Cache.Address) {
+				return []string{"redis"}
+			}
+
+			return nil
+		},
+		init: func(r *Runtime) (err error) {
+			cc := *r.cfg.Cache
+			if sharesRedis(cc.Driver, DefaultCacheDriver, cc.Address) {
+				cc.Address, cc.MasterName, cc.Password, cc.DB = sharedRedis(r.cfg.Redis, cc.DB)
+			}
+
+			r.ch, err = r.initCache(&cc)
+
+			return
+		},
+	},
+	{
+		name:    "store",
+		enabled: func(cfg *Config) bool { return cfg.Store != nil },
+		deps: func(cfg *Config) []string {
+			if sharesRedis(cfg.Store.Driver, DefaultStoreDriver, cfg.Store.Address) {
+				return []string{"redis"}
+			}
+
+			return nil
+		},
+		init: func(r *Runtime) (err error) {
+			sc := *r.cfg.Store
+			if sharesRedis(sc.Driver, DefaultStoreDriver, sc.Address) {
+				sc.Address, sc.MasterName, sc.Password, sc.DB = sharedRedis(r.cfg.Redis, sc.DB)
+			}
+
+			r.st, err = r.initStore(&sc)
+
+			return
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			return r.st.Close()
+		},
+	},
+	{
+		name:    "database",
+		enabled: func(cfg *Config) bool { return cfg.Database != nil },
+		init: func(r *Runtime) error {
+			d, err := r.initDatabase("", r.cfg.Database)
+			if err != nil {
+				return err
+			}
+
+			r.db, r.queryLog, r.replicas = d.db, d.queryLog, d.replicas
+
+			return nil
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			d := &database{db: r.db, replicas: r.replicas}
+
+			return d.close()
+		},
+	},
+	{
+		name:    "databases",
+		enabled: func(cfg *Config) bool { return len(cfg.Databases) > 0 },
+		init: func(r *Runtime) error {
+			return r.initDatabases(r.cfg.Databases)
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			return r.closeDatabases()
+		},
+	},
+	{
+		name:    "mongo",
+		enabled: func(cfg *Config) bool { return cfg.Mongo != nil },
+		init: func(r *Runtime) (err error) {
+			r.mdb, err = r.initMongo(r.cfg.Mongo)
+
+			return
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			return r.mdb.Disconnect(ctx)
+		},
+	},
+	{
+		name:    "redis",
+		enabled: func(cfg *Config) bool { return cfg.Redis != nil },
+		init: func(r *Runtime) (err error) {
+			r.rdb, err = r.initRedis(r.cfg.Redis)
+
+			return
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			return r.rdb.Close()
+		},
+	},
+	{
+		name:    "fiber",
+		enabled: func(cfg *Config) bool { return cfg.Fiber != nil },
+		init: func(r *Runtime) (err error) {
+			r.fb, err = r.initFiber(r.cfg.Fiber)
+
+			return
+		},
+		close: func(ctx context.Context, r *Runtime) error {
+			r.log.Info("stopping fiber server")
+
+			return r.fb.ShutdownWithContext(ctx)
+		},
+	},
+	{
+		name:    "session",
+		enabled: func(cfg *Config) bool { return cfg.Session != nil },
+		deps: func(cfg *Config) []string {
+			return []string{"redis", "fiber"}
+		},
+		init: func(r *Runtime) error {
+			return r.initSession(r.cfg.Session)
+		},
+	},
+	{
+		name:    "tenant",
+		enabled: func(cfg *Config) bool { return cfg.Tenant != nil },
+		deps: func(cfg *Config) []string {
+			if strings.ToLower(cfg.Tenant.Source) == "header" {
+				return []string{"fiber"}
+			}
+
+			return []string{"fiber", "session"}
+		},
+		init: func(r *Runtime) error {
+			return r.initTenant(r.cfg.Tenant)
+		},
+	},
+}
+
+// sharesRedis reports whether a redis driven cache or store has no address of
+// its own. go-micro plugins are built on go-redis v8, so the redis settings
+// are shared instead of the client.
+func sharesRedis(driver, def string, addrs []string) bool {
+	if driver == "" {
+		driver = def
+	}
+
+	return strings.ToLower(driver) == "redis" && len(addrs) == 0
+}
+
+func sharedRedis(cfg *configRedis, db int) ([]string, string, Secret, int) {
+	if db == 0 {
+		db = cfg.DB
+	}
+
+	return cfg.Address, cfg.MasterName, cfg.Password, db
+}
+
+// componentOrder returns enabled components sorted by dependencies
+func componentOrder(cfg *Config) ([]*component, error) {
+	var (
+		errs   error
+		order  []*component
+		byName = make(map[string]*component, len(components))
+		state  = make(map[string]int)
+		visit  func(c *component, from string) bool
+	)
+
+	const (
+		walking = 1
+		visited = 2
+	)
+
+	for _, c := range components {
+		if c.enabled(cfg) {
+			byName[c.name] = c
+		}
+	}
+
+	visit = func(c *component, from string) bool {
+		switch state[c.name] {
+		case visited:
+			return true
+		case walking:
+			errs = errors.Join(errs, fmt.Errorf("%s.%s: dependency cycle via %s", BaseConfigKey, c.name, from))
+
+			return false
+		}
+
+		state[c.name] = walking
+		ok := true
+		if c.deps != nil {
+			for _, dep := range c.deps(cfg) {
+				d, found := byName[dep]
+				if !found {
+					errs = errors.Join(errs, fmt.Errorf("%s.%s: requires %s section", BaseConfigKey, c.name, dep))
+					ok = false
+
+					continue
+				}
+
+				ok = visit(d, c.name) && ok
+			}
+		}
+
+		state[c.name] = visited
+		if ok {
+			order = append(order, c)
+		}
+
+		return ok
+	}
+
+	for _, c := range components {
+		if byName[c.name] != nil {
+			visit(c, "")
+		}
+	}
+
+	return order, errs
+}
+
+// start initializes components in dependency order, dependents of a failed
+// component are skipped
+func (r *Runtime) start() error {
+	var errs error
+
+	order, err := componentOrder(r.cfg)
+	if err != nil {
+		return err
+	}
+
+	failed := make(map[string]bool)
+	for _, c := range order {
+		var deps []string
+		if c.deps != nil {
+			deps = c.deps(r.cfg)
+		}
+
+		skip := false
+		for _, dep := range deps {
+			if failed[dep] {
+				errs = errors.Join(errs, fmt.Errorf("%s skipped: dependency %s failed", c.name, dep))
+				skip = true
+
+				break
+			}
+		}
+
+		if !skip {
+			err = c.init(r)
+			if err != nil {
+				errs = errors.Join(errs, err)
+			} else {
+				r.started = append(r.started, c)
+
+				continue
+			}
+		}
+
+		failed[c.name] = true
+	}
+
+	return errs
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file graph_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComponentOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+		err  string
+	}{
+		{
+			name: "declaration order",
+			src:  `{"registry": {}, "broker": {}, "fiber": {}}`,
+			want: "registry,broker,fiber",
+		},
+		{
+			name: "shared redis first",
+			src:  `{"cache": {"driver": "redis"}, "store": {"driver": "redis", "address": ["s:1"]}, "redis": {"address": ["r:1"]}}`,
+			want: "redis,cache,store",
+		},
+		{
+			name: "session and tenant",
+			src:  `{"tenant": {}, "session": {}, "fiber": {}, "redis": {"address": ["r:1"]}}`,
+			want: "redis,fiber,session,tenant",
+		},
+		{
+			name: "header tenant without session",
+			src:  `{"tenant": {"source": "header"}, "fiber": {}}`,
+			want: "fiber,tenant",
+		},
+		{
+			name: "missing dependency",
+			src:  `{"session": {}, "fiber": {}}`,
+			err:  "base.session: requires redis section",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := componentOrder(testConfig(t, tt.src))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got %v, want %q", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			names := make([]string, 0, len(order))
+			for _, c := range order {
+				names = append(names, c.name)
+			}
+
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
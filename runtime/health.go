@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file health.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zenkoo-live/svc.base/utils"
+)
+
+const (
+	DefaultLivenessPath       = "/healthz"
+	DefaultReadinessPath      = "/readyz"
+	DefaultHealthCheckTimeout = 3000
+
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheck probes one component, returns nil if healthy
+type HealthCheck func(ctx context.Context) error
+
+type HealthResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status     string                   `json:"status"`
+	Components map[string]*HealthResult `json:"components"`
+}
+
+// RegisterHealthCheck adds a custom check reported by readiness probe
+func (r *Runtime) RegisterHealthCheck(name string, check HealthCheck) {
+	r.hcMu.Lock()
+	defer r.hcMu.Unlock()
+
+	if r.healthChecks == nil {
+		r.healthChecks = make(map[string]HealthCheck)
+	}
+
+	r.healthChecks[name] = check
+}
+
+func (r *Runtime) builtinHealthChecks() map[string]HealthCheck {
+	checks := make(map[string]HealthCheck)
+	if r.db != nil {
+		checks["database"] = func(ctx context.Context) error {
+			return r.db.PingContext(ctx)
+		}
+	}
+
+	for name, d := range r.dbs {
+		db := d.db
+		checks["database."+name] = func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		}
+	}
+
+	if r.rdb != nil {
+		checks["redis"] = func(ctx context.Context) error {
+			return r.rdb.Ping(ctx).Err()
+		}
+	}
+
+	if r.mdb != nil {
+		checks["mongo"] = func(ctx context.Context) error {
+			return r.mdb.Ping(ctx, nil)
+		}
+	}
+
+	if r.cfg.Broker != nil {
+		checks["broker"] = func(ctx context.Context) error {
+			if r.brk == nil || !r.brkConnected.Load() {
+				return errors.New("broker not connected")
+			}
+
+			// Drivers may expose their connection state
+			if c, ok := r.brk.(interface{ IsConnected() bool }); ok && !c.IsConnected() {
+				return errors.New("broker disconnected")
+			}
+
+			return nil
+		}
+	}
+
+	if r.cfg.Registry != nil {
+		checks["registry"] = func(ctx context.Context) error {
+			if r.rg == nil {
+				return errors.New("registry not initialized")
+			}
+
+			_, err := r.rg.ListServices()
+
+			return err
+		}
+	}
+
+	return checks
+}
+
+// CheckHealth runs all builtin and registered checks concurrently
+func (r *Runtime) CheckHealth(ctx context.Context) (*HealthReport, bool) {
+	checks := r.builtinHealthChecks()
+	r.hcMu.RLock()
+	for name, check := range r.healthChecks {
+		checks[name] = check
+	}
+	r.hcMu.RUnlock()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		healthy = true
+		report  = &HealthReport{
+			Status:     HealthStatusUp,
+			Components: make(map[string]*HealthResult, len(checks)),
+		}
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := runHealthCheck(ctx, check)
+			result := &HealthResult{
+				Status:  HealthStatusUp,
+				Latency: time.Since(start).String(),
+			}
+			if err != nil {
+				result.Status = HealthStatusDown
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Components[name] = result
+			if err != nil {
+				healthy = false
+				report.Status = HealthStatusDown
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return report, healthy
+}
+
+func (r *Runtime) mountHealthCheck(app *fiber.App, cfg *configFiber) {
+	timeout := time.Duration(cfg.HealthCheckTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout * time.Millisecond
+	}
+
+	handler := func(strict bool) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+			defer cancel()
+
+			report, healthy := r.CheckHealth(ctx)
+			resp := utils.WrapResponse(report)
+			if rid, ok := c.Locals("requestid").(string); ok {
+				resp.SetRequestId(rid)
+			}
+
+			if !healthy && strict {
+				resp.SetStatus(fiber.StatusServiceUnavailable)
+				resp.SetCode(utils.CodeHealthCheckFailed)
+				resp.SetMessage(utils.MsgHealthCheckFailed)
+			}
+
+			return c.Status(resp.Status).JSON(resp)
+		}
+	}
+
+	// Liveness reports components but never fails, a broken dependency
+	// should not restart the process
+	app.Get(DefaultLivenessPath, handler(false))
+	app.Get(DefaultReadinessPath, handler(true))
+}
+
+func RegisterHealthCheck(name string, check HealthCheck) {
+	defaultRuntime.RegisterHealthCheck(name, check)
+}
+
+func CheckHealth(ctx context.Context) (*HealthReport, bool) {
+	return defaultRuntime.CheckHealth(ctx)
+}
+
+func runHealthCheck(ctx context.Context, check HealthCheck) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file hook.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexlast/bunzap"
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.uber.org/zap"
+)
+
+// queryLogHook wraps bunzap with switches which could be changed on reload
+type queryLogHook struct {
+	logger *zap.Logger
+	// Built by set, nil if query log is off
+	hook atomic.Pointer[bunzap.QueryHook]
+}
+
+func newQueryLogHook(logger *zap.Logger, cfg *configDatabase) *queryLogHook {
+	h := &queryLogHook{
+		logger: logger,
+	}
+	h.set(cfg)
+
+	return h
+}
+
+func (h *queryLogHook) set(cfg *configDatabase) {
+	var slow time.Duration
+	if !cfg.Debug {
+		slow = time.Duration(cfg.SlowQueryDuration) * time.Millisecond
+		if slow <= 0 {
+			h.hook.Store(nil)
+
+			return
+		}
+	}
+
+	hook := bunzap.NewQueryHook(bunzap.QueryHookOptions{
+		Logger:       h.logger,
+		SlowDuration: slow,
+	})
+	h.hook.Store(&hook)
+}
+
+func (h *queryLogHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *queryLogHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if hook := h.hook.Load(); hook != nil {
+		hook.AfterQuery(ctx, event)
+	}
+}
+
+// commandLogMonitor logs mongo commands, with switches which could be
+// changed on reload
+type commandLogMonitor struct {
+	logger *zap.Logger
+	debug  atomic.Bool
+	slow   atomic.Int64
+	// Collections of started commands by request ID, finished events have
+	// no command body
+	collections sync.Map
+}
+
+func newCommandLogMonitor(logger *zap.Logger, cfg *configMongo) *commandLogMonitor {
+	m := &commandLogMonitor{
+		logger: logger,
+	}
+	m.set(cfg)
+
+	return m
+}
+
+func (m *commandLogMonitor) set(cfg *configMongo) {
+	m.debug.Store(cfg.Debug)
+	m.slow.Store(int64(time.Duration(cfg.SlowCommandDuration) * time.Millisecond))
+}
+
+func (m *commandLogMonitor) enabled() bool {
+	return m.debug.Load() || m.slow.Load() > 0
+}
+
+func (m *commandLogMonitor) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *commandLogMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	if m.enabled() {
+		m.collections.Store(evt.RequestID, commandCollection(evt.Command))
+	}
+}
+
+func (m *commandLogMonitor) succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	fields, ok := m.fields(ctx, &evt.CommandFinishedEvent)
+	if !ok {
+		return
+	}
+
+	if m.debug.Load() {
+		m.logger.Debug("mongo "+evt.CommandName, fields...)
+	} else if slow := time.Duration(m.slow.Load()); slow > 0 && evt.Duration >= slow {
+		m.logger.Warn("mongo slow "+evt.CommandName, fields...)
+	}
+}
+
+func (m *commandLogMonitor) failed(ctx context.Context, evt *event.CommandFailedEvent) {
+	fields, ok := m.fields(ctx, &evt.CommandFinishedEvent)
+	if !ok {
+		return
+	}
+
+	// Failures are always logged while monitor is on
+	m.logger.Error("mongo "+evt.CommandName, append(fields, zap.String("error", evt.Failure))...)
+}
+
+func (m *commandLogMonitor) fields(ctx context.Context, evt *event.CommandFinishedEvent) ([]zap.Field, bool) {
+	collection, _ := m.collections.LoadAndDelete(evt.RequestID)
+	if !m.enabled() {
+		return nil, false
+	}
+
+	fields := []zap.Field{
+		zap.String("database", evt.DatabaseName),
+		zap.String("command", evt.CommandName),
+		zap.Duration("duration", evt.Duration),
+	}
+	if c, _ := collection.(string); c != "" {
+		fields = append(fields, zap.String("collection", c))
+	}
+
+	if rid := utils.RequestIDFromContext(ctx); rid != "" {
+		fields = append(fields, zap.String("requestId", rid))
+	}
+
+	return fields, true
+}
+
+// commandCollection returns collection of command, which is the value of
+// first element, or collection element for getMore
+func commandCollection(cmd bson.Raw) string {
+	elems, err := cmd.Elements()
+	if err != nil || len(elems) == 0 {
+		return ""
+	}
+
+	if c, ok := elems[0].Value().StringValueOK(); ok {
+		return c
+	}
+
+	c, _ := cmd.Lookup("collection").StringValueOK()
+
+	return c
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file hook_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestQueryLogHook(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *configDatabase
+		took time.Duration
+		want int
+	}{
+		{"off", &configDatabase{}, time.Second, 0},
+		{"debug", &configDatabase{Debug: true}, 0, 1},
+		{"fast", &configDatabase{SlowQueryDuration: 100}, 0, 0},
+		{"slow", &configDatabase{SlowQueryDuration: 100}, time.Second, 1},
+	}
+
+	core, logs := observer.New(zap.DebugLevel)
+	h := newQueryLogHook(zap.New(core), &configDatabase{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Same hook reloaded with each config
+			h.set(tt.cfg)
+			logs.TakeAll()
+			h.AfterQuery(context.Background(), &bun.QueryEvent{Query: "SELECT 1", StartTime: time.Now().Add(-tt.took)})
+			if n := logs.Len(); n != tt.want {
+				t.Errorf("logged %d queries, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file migrate.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/migrate"
+)
+
+var (
+	migrations   = make(map[string]*migrate.Migrations)
+	migrationsMu sync.RWMutex
+)
+
+// RegisterMigrations binds migrations to a database, DefaultDatabaseName
+// refers to DB(). Should be called before Init.
+func RegisterMigrations(database string, ms *migrate.Migrations) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	migrations[database] = ms
+}
+
+func registeredMigrations(database string) *migrate.Migrations {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	return migrations[database]
+}
+
+func migratorOptions(cfg *configMigrate) []migrate.Option {
+	var opts []migrate.Option
+	if cfg == nil {
+		return opts
+	}
+
+	if cfg.Table != "" {
+		opts = append(opts, migrate.WithTable(cfg.Table))
+	}
+
+	if cfg.LocksTable != "" {
+		opts = append(opts, migrate.WithLocksTable(cfg.LocksTable))
+	}
+
+	if cfg.LockTimeout > 0 {
+		opts = append(opts, migrate.WithLockTimeout(time.Duration(cfg.LockTimeout)*time.Millisecond))
+	}
+
+	if cfg.LockTTL > 0 {
+		opts = append(opts, migrate.WithLockTTL(time.Duration(cfg.LockTTL)*time.Millisecond))
+	}
+
+	return opts
+}
+
+// migrate applies pending migrations if auto migrate enabled
+func (r *Runtime) migrate(label, name string, cfg *configDatabase, db *bun.DB) error {
+	if cfg.Migrate == nil || !cfg.Migrate.Auto {
+		return nil
+	}
+
+	ms := registeredMigrations(name)
+	if ms == nil {
+		r.log.Warnf("%s auto migrate enabled, but no migrations registered", label)
+
+		return nil
+	}
+
+	group, err := migrate.NewMigrator(db, ms, migratorOptions(cfg.Migrate)...).Up(context.Background())
+	if err != nil {
+		return fmt.Errorf("%s migrate : %w", label, err)
+	}
+
+	if group.IsZero() {
+		r.log.Infof("%s schema is up to date", label)
+	} else {
+		r.log.Infof("%s migrated to %s", label, group)
+	}
+
+	return nil
+}
+
+// Migrator returns migrator of database for up, down, status and mark
+// applied commands. Returns nil if database or migrations not found.
+func (r *Runtime) Migrator(name string) *migrate.Migrator {
+	cfg := r.databaseConfig(name)
+	db := r.DBNamed(name)
+	ms := registeredMigrations(name)
+	if cfg == nil || db == nil || ms == nil {
+		return nil
+	}
+
+	return migrate.NewMigrator(db, ms, migratorOptions(cfg.Migrate)...)
+}
+
+func Migrator(name string) *migrate.Migrator {
+	return defaultRuntime.Migrator(name)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,317 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file mongo.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zenkoo-live/svc.base/mongoschema"
+	"github.com/zenkoo-live/svc.base/utils"
+	"go-micro.dev/v4/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// mongoOptions builds client options from DSN, non-zero settings of cfg
+// override the ones in DSN
+func mongoOptions(cfg *configMongo) (*options.ClientOptions, error) {
+	opts := options.Client().ApplyURI(cfg.DSN.Value())
+	if cfg.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(uint64(cfg.MaxPoolSize))
+	}
+
+	if cfg.MinPoolSize > 0 {
+		opts.SetMinPoolSize(uint64(cfg.MinPoolSize))
+	}
+
+	if cfg.ConnectTimeout > 0 {
+		opts.SetConnectTimeout(time.Duration(cfg.ConnectTimeout) * time.Millisecond)
+	}
+
+	if cfg.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(time.Duration(cfg.ServerSelectionTimeout) * time.Millisecond)
+	}
+
+	if cfg.ReadPreference != "" {
+		rp, err := mongoReadPref(cfg.ReadPreference)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.SetReadPreference(rp)
+	}
+
+	if cfg.WriteConcern != "" {
+		wc, err := mongoWriteConcern(cfg.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.SetWriteConcern(wc)
+	}
+
+	return opts, opts.Validate()
+}
+
+func mongoReadPref(v string) (*readpref.ReadPref, error) {
+	mode, err := readpref.ModeFromString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return readpref.New(mode)
+}
+
+func mongoWriteConcern(v string) (*writeconcern.WriteConcern, error) {
+	if strings.EqualFold(v, "majority") {
+		return writeconcern.Majority(), nil
+	}
+
+	w, err := strconv.Atoi(v)
+	if err != nil || w < 0 {
+		return nil, fmt.Errorf("unknown write concern %s", v)
+	}
+
+	return &writeconcern.WriteConcern{W: w}, nil
+}
+
+// mongoDatabaseName returns configured database, or the one in DSN
+func mongoDatabaseName(cfg *configMongo) string {
+	if cfg.Database != "" {
+		return cfg.Database
+	}
+
+	cs, err := connstring.Parse(cfg.DSN.Value())
+	if err != nil {
+		return ""
+	}
+
+	return cs.Database
+}
+
+const (
+	// Error labels, the whole transaction could be replayed
+	mongoTransientTxError    = "TransientTransactionError"
+	mongoUnknownCommitResult = "UnknownTransactionCommitResult"
+)
+
+// mongoTxKey holds session of the running MongoTx
+type mongoTxKey struct{}
+
+var (
+	mongoSchema   *mongoschema.Schema
+	mongoSchemaMu sync.RWMutex
+)
+
+// RegisterMongoSchema binds collections and migrations to MongoDB(). Should
+// be called before Init.
+func RegisterMongoSchema(s *mongoschema.Schema) {
+	mongoSchemaMu.Lock()
+	defer mongoSchemaMu.Unlock()
+
+	mongoSchema = s
+}
+
+func registeredMongoSchema() *mongoschema.Schema {
+	mongoSchemaMu.RLock()
+	defer mongoSchemaMu.RUnlock()
+
+	return mongoSchema
+}
+
+func mongoMigratorOptions(cfg *configMigrate) []mongoschema.Option {
+	var opts []mongoschema.Option
+	if cfg == nil {
+		return opts
+	}
+
+	if cfg.Table != "" {
+		opts = append(opts, mongoschema.WithCollection(cfg.Table))
+	}
+
+	if cfg.LocksTable != "" {
+		opts = append(opts, mongoschema.WithLocksCollection(cfg.LocksTable))
+	}
+
+	if cfg.LockTimeout > 0 {
+		opts = append(opts, mongoschema.WithLockTimeout(time.Duration(cfg.LockTimeout)*time.Millisecond))
+	}
+
+	if cfg.LockTTL > 0 {
+		opts = append(opts, mongoschema.WithLockTTL(time.Duration(cfg.LockTTL)*time.Millisecond))
+	}
+
+	return opts
+}
+
+// migrateMongo reconciles collections and applies pending migrations if
+// auto migrate enabled, drift is only logged
+func (r *Runtime) migrateMongo(cfg *configMongo, db *mongo.Database) error {
+	if cfg.Migrate == nil || !cfg.Migrate.Auto {
+		return nil
+	}
+
+	s := registeredMongoSchema()
+	if s == nil {
+		r.log.Warnf("mongo auto migrate enabled, but no schema registered")
+
+		return nil
+	}
+
+	if db == nil {
+		return errors.New("mongo migrate requires database in config or DSN")
+	}
+
+	m := mongoschema.NewMigrator(db, s, mongoMigratorOptions(cfg.Migrate)...)
+	report, err := m.Reconcile(context.Background())
+	if err != nil {
+		return fmt.Errorf("mongo reconcile : %w", err)
+	}
+
+	for _, c := range report.Collections {
+		r.log.Infof("mongo collection %s created", c)
+	}
+
+	for _, idx := range report.Indexes {
+		r.log.Infof("mongo index %s created", idx)
+	}
+
+	for _, d := range report.Drifts {
+		r.log.Warnf("mongo schema drift %s", d)
+	}
+
+	applied, err := m.Up(context.Background())
+	if err != nil {
+		return fmt.Errorf("mongo migrate : %w", err)
+	}
+
+	if len(applied) == 0 {
+		r.log.Infof("mongo schema is up to date")
+	} else {
+		r.log.Infof("mongo migrated %d migrations to %s", len(applied), applied[len(applied)-1].Name)
+	}
+
+	return nil
+}
+
+// MongoMigrator returns migrator of MongoDB() for reconcile, up, status and
+// mark applied commands. Returns nil if database or schema not found.
+func (r *Runtime) MongoMigrator() *mongoschema.Migrator {
+	s := registeredMongoSchema()
+	if r.mdbDatabase == nil || s == nil {
+		return nil
+	}
+
+	return mongoschema.NewMigrator(r.mdbDatabase, s, mongoMigratorOptions(r.cfg.Mongo.Migrate)...)
+}
+
+func MongoMigrator() *mongoschema.Migrator {
+	return defaultRuntime.MongoMigrator()
+}
+
+func retryableMongoTxError(err error) bool {
+	var le mongo.LabeledError
+	if errors.As(err, &le) {
+		return le.HasErrorLabel(mongoTransientTxError) || le.HasErrorLabel(mongoUnknownCommitResult)
+	}
+
+	return false
+}
+
+// MongoTx runs fn in a transaction of a new session. WithTransaction retries
+// fn on transient errors and commit on unknown result for up to 120
+// seconds, errors it gives up on are replayed with tx_retry backoff, so fn
+// should have no side effects besides mongo. Called inside another MongoTx,
+// fn joins the outer transaction, mongo has no nested ones.
+func (r *Runtime) MongoTx(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	// ctx may be wrapped by callers, so look for the session instead of
+	// asserting SessionContext
+	if sess, ok := ctx.Value(mongoTxKey{}).(mongo.Session); ok && mongo.SessionFromContext(ctx) == sess {
+		return fn(mongo.NewSessionContext(ctx, sess))
+	}
+
+	if r.mdb == nil {
+		return errors.New("mongo not initialized")
+	}
+
+	cfg := defaultTxRetry
+	if r.cfg.Mongo != nil && r.cfg.Mongo.TxRetry != nil {
+		cfg = r.cfg.Mongo.TxRetry
+	}
+
+	log := r.log
+	if rid := utils.RequestIDFromContext(ctx); rid != "" {
+		log = r.log.WithFields(map[string]interface{}{"requestId": rid})
+	}
+
+	attempts := cfg.attempts()
+	for attempt := 1; ; attempt++ {
+		err := r.mongoTx(ctx, log, fn)
+		if err == nil || attempt >= attempts || !retryableMongoTxError(err) {
+			return err
+		}
+
+		d := cfg.delay(attempt)
+		log.Warnf("mongo transaction attempt %d/%d failed : %s, retry in %s", attempt, attempts, err.Error(), d)
+
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+func (r *Runtime) mongoTx(ctx context.Context, log *logger.Helper, fn func(sessCtx mongo.SessionContext) error) error {
+	sess, err := r.mdb.StartSession()
+	if err != nil {
+		return err
+	}
+
+	defer sess.EndSession(context.WithoutCancel(ctx))
+
+	calls := 0
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		calls++
+		if calls > 1 {
+			log.Warnf("mongo transaction replayed after transient error, call %d", calls)
+		}
+
+		return nil, fn(mongo.NewSessionContext(context.WithValue(sc, mongoTxKey{}, sess), sess))
+	})
+
+	return err
+}
+
+func MongoTx(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	return defaultRuntime.MongoTx(ctx, fn)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file mongo_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestRetryableMongoTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("boom"), false},
+		{"transient", mongo.CommandError{Labels: []string{mongoTransientTxError}}, true},
+		{"unknown commit", mongo.CommandError{Labels: []string{mongoUnknownCommitResult}}, true},
+		{"other label", mongo.CommandError{Labels: []string{"RetryableWriteError"}}, false},
+		{"write exception", mongo.WriteException{Labels: []string{mongoTransientTxError}}, true},
+		{"wrapped", fmt.Errorf("insert: %w", mongo.CommandError{Labels: []string{mongoTransientTxError}}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryableMongoTxError(tt.err); got != tt.want {
+				t.Errorf("retryableMongoTxError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMongoTxNested(t *testing.T) {
+	// Sessions start without server
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	sess, err := client.StartSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer sess.EndSession(context.Background())
+
+	// Context of an outer MongoTx, see mongoTx. r has no mongo, so only
+	// joining it succeeds
+	r := &Runtime{cfg: &Config{}}
+	outer := mongo.NewSessionContext(context.WithValue(context.Background(), mongoTxKey{}, sess), sess)
+
+	type key struct{}
+	wrapped, cancel := context.WithTimeout(context.WithValue(outer, key{}, 1), time.Minute)
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		joined bool
+	}{
+		{"session context", outer, true},
+		{"wrapped", wrapped, true},
+		{"no transaction", context.Background(), false},
+		{"session without MongoTx", mongo.NewSessionContext(context.Background(), sess), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined := false
+			err := r.MongoTx(tt.ctx, func(sc mongo.SessionContext) error {
+				joined = sc.Client() == sess.Client() && mongo.SessionFromContext(sc) == sess
+
+				return nil
+			})
+			if joined != tt.joined || (err == nil) != tt.joined {
+				t.Errorf("joined %v with error %v, want joined %v", joined, err, tt.joined)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
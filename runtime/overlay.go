@@ -0,0 +1,231 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file overlay.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+/*
+ * Config sources are loaded in order, later ones override earlier:
+ *
+ *   1. file    config.file.path, e.g. config.yaml
+ *   2. file    per-env file beside it, e.g. config.<env>.yaml
+ *   3. consul  config.consul.prefix
+ *   4. env     BASE_DATABASE_DSN=...       -> base.database.dsn
+ *   5. flags   --base.database.dsn=...     -> base.database.dsn
+ *
+ * Env and flag keys must match fields of Config, list values are comma separated.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go-micro.dev/v4/config/source"
+	"go-micro.dev/v4/config/source/memory"
+)
+
+const (
+	FlagPrefix = "--" + BaseConfigKey + "."
+)
+
+type configField struct {
+	path []string
+	typ  reflect.Type
+}
+
+func configFields(t reflect.Type, prefix []string) []configField {
+	var fields []configField
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		path := append(append([]string{}, prefix...), name)
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		switch ft.Kind() {
+		case reflect.Struct:
+			fields = append(fields, configFields(ft, path)...)
+		case reflect.Map:
+			// Not addressable by single key
+		default:
+			fields = append(fields, configField{path: path, typ: ft})
+		}
+	}
+
+	return fields
+}
+
+func parseConfigValue(typ reflect.Type, raw string) (any, error) {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(raw, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	case reflect.Slice:
+		var list []string
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+
+		return list, nil
+	default:
+		return raw, nil
+	}
+}
+
+func setConfigValue(m map[string]any, path []string, value any) {
+	for _, k := range path[:len(path)-1] {
+		sub, ok := m[k].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			m[k] = sub
+		}
+
+		m = sub
+	}
+
+	m[path[len(path)-1]] = value
+}
+
+// overlaySource builds a source from environment variables and command-line
+// flags, returns nil if none of them matches a config field
+func overlaySource(environ, args []string) (source.Source, error) {
+	var (
+		fields  = configFields(reflect.TypeOf(Config{}), []string{BaseConfigKey})
+		byEnv   = make(map[string]configField, len(fields))
+		byFlag  = make(map[string]configField, len(fields))
+		overlay = make(map[string]any)
+		found   bool
+	)
+
+	for _, f := range fields {
+		byEnv[strings.ToUpper(strings.Join(f.path, "_"))] = f
+		byFlag[strings.Join(f.path, ".")] = f
+	}
+
+	set := func(f configField, raw, from string) error {
+		v, err := parseConfigValue(f.typ, raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", from, err)
+		}
+
+		setConfigValue(overlay, f.path, v)
+		found = true
+
+		return nil
+	}
+
+	// Env
+	for _, kv := range environ {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+
+		if f, ok := byEnv[k]; ok {
+			if err := set(f, v, k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Flags
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-"+BaseConfigKey+".") {
+			arg = "-" + arg
+		}
+
+		if !strings.HasPrefix(arg, FlagPrefix) {
+			continue
+		}
+
+		k, v, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		f, ok := byFlag[k]
+		if !ok {
+			return nil, fmt.Errorf("flag --%s: unknown config key", k)
+		}
+
+		// Bare bool flag is true, like flag package
+		if !hasValue {
+			if f.typ.Kind() == reflect.Bool {
+				v = "true"
+			} else if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag --%s: value required", k)
+			} else {
+				i++
+				v = args[i]
+			}
+		}
+
+		if err := set(f, v, "flag --"+k); err != nil {
+			return nil, err
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.NewSource(memory.WithJSON(b)), nil
+}
+
+// envFilePath returns config.<env>.yaml beside config.yaml if exists
+func envFilePath(path, env string) string {
+	if path == "" || env == "" {
+		return ""
+	}
+
+	ext := filepath.Ext(path)
+	p := strings.TrimSuffix(path, ext) + "." + env + ext
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+
+	return p
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file overlay_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func overlayMap(t *testing.T, environ, args []string) map[string]any {
+	t.Helper()
+
+	src, err := overlaySource(environ, args)
+	if err != nil {
+		t.Fatalf("overlaySource: %s", err)
+	}
+
+	if src == nil {
+		return nil
+	}
+
+	cs, err := src.Read()
+	if err != nil {
+		t.Fatalf("read overlay: %s", err)
+	}
+
+	var m map[string]any
+	err = json.Unmarshal(cs.Data, &m)
+	if err != nil {
+		t.Fatalf("decode overlay: %s", err)
+	}
+
+	return m
+}
+
+func TestOverlaySource(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		args    []string
+		want    map[string]any
+	}{
+		{
+			name: "none",
+			args: []string{"--other", "x"},
+			want: nil,
+		},
+		{
+			name:    "env",
+			environ: []string{"BASE_REDIS_DB=2", "BASE_REDIS_PASSWORD=p", "PATH=/bin"},
+			want:    map[string]any{"base": map[string]any{"redis": map[string]any{"db": 2.0, "password": "p"}}},
+		},
+		{
+			name: "flag with value",
+			args: []string{"--base.fiber.address=:8080", "-base.database.dsn", "file:x"},
+			want: map[string]any{"base": map[string]any{
+				"fiber":    map[string]any{"address": ":8080"},
+				"database": map[string]any{"dsn": "file:x"},
+			}},
+		},
+		{
+			name: "bare bool flag",
+			args: []string{"--base.logger.debug", "--base.logger.silence=false", "run"},
+			want: map[string]any{"base": map[string]any{"logger": map[string]any{"debug": true, "silence": false}}},
+		},
+		{
+			name:    "list",
+			environ: []string{"BASE_BROKER_ADDRESS=a:1, b:2"},
+			want:    map[string]any{"base": map[string]any{"broker": map[string]any{"address": []any{"a:1", "b:2"}}}},
+		},
+		{
+			name:    "named list",
+			environ: []string{"BASE_REDIS_ADDRESS=a:6379"},
+			want:    map[string]any{"base": map[string]any{"redis": map[string]any{"address": []any{"a:6379"}}}},
+		},
+		{
+			name:    "flag overrides env",
+			environ: []string{"BASE_LOGGER_DEBUG=false"},
+			args:    []string{"--base.logger.debug"},
+			want:    map[string]any{"base": map[string]any{"logger": map[string]any{"debug": true}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := overlayMap(t, tt.environ, tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlaySourceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		args    []string
+	}{
+		{name: "unknown key", args: []string{"--base.nothing=1"}},
+		{name: "missing value", args: []string{"--base.fiber.address"}},
+		{name: "bad bool", args: []string{"--base.logger.debug=maybe"}},
+		{name: "bad int env", environ: []string{"BASE_REDIS_DB=x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := overlaySource(tt.environ, tt.args)
+			if err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
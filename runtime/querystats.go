@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file querystats.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type queryStatsKey struct{}
+
+var (
+	shapeLiteral = regexp.MustCompile(`'(?:[^']|'')*'|\b\d+(?:\.\d+)?\b`)
+	shapeList    = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// queryStats accumulates queries of one request
+type queryStats struct {
+	mu       sync.Mutex
+	queries  int
+	duration time.Duration
+	// nil if repeats not tracked
+	shapes map[string]int
+}
+
+func (s *queryStats) add(query string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries++
+	s.duration += d
+	if s.shapes != nil {
+		s.shapes[queryShape(query)]++
+	}
+}
+
+func (s *queryStats) totals() (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.queries, s.duration
+}
+
+// queryShape strips literals, so the same query with different arguments
+// counts as a repeat
+func queryShape(query string) string {
+	return shapeList.ReplaceAllString(shapeLiteral.ReplaceAllString(query, "?"), "(?)")
+}
+
+// queryStatsHook feeds queryStats in query context, if any
+type queryStatsHook struct{}
+
+func (queryStatsHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (queryStatsHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if s, ok := ctx.Value(queryStatsKey{}).(*queryStats); ok {
+		s.add(event.Query, time.Since(event.StartTime))
+	}
+}
+
+// queryStatsHandler puts queryStats into user context of request, warns on
+// too many or repeated queries after handler
+func (r *Runtime) queryStatsHandler(cfg *configQueryStats) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		s := &queryStats{}
+		if cfg.MaxRepeats > 0 {
+			s.shapes = make(map[string]int)
+		}
+
+		c.SetUserContext(context.WithValue(c.UserContext(), queryStatsKey{}, s))
+		rid, _ := c.Locals("requestid").(string)
+		if rid != "" {
+			r.reqStats.Store(rid, s)
+			defer r.reqStats.Delete(rid)
+		}
+
+		err := c.Next()
+
+		queries, duration := s.totals()
+		log := r.log.WithFields(map[string]interface{}{"requestId": rid})
+		if cfg.MaxQueries > 0 && queries > cfg.MaxQueries {
+			log.Warnf("%s %s ran %d queries in %s, exceeds %d", c.Method(), c.OriginalURL(), queries, duration, cfg.MaxQueries)
+		}
+
+		s.mu.Lock()
+		for shape, n := range s.shapes {
+			if n > cfg.MaxRepeats {
+				log.Warnf("%s %s repeated query %d times, possible N+1 : %s", c.Method(), c.OriginalURL(), n, shape)
+			}
+		}
+		s.mu.Unlock()
+
+		return err
+	}
+}
+
+// accessLogCore appends query totals to fiberzap entries by requestId field,
+// fiberzap has no custom fields
+type accessLogCore struct {
+	zapcore.Core
+	stats *sync.Map
+}
+
+func (r *Runtime) wrapAccessLogCore(core zapcore.Core) zapcore.Core {
+	return &accessLogCore{Core: core, stats: &r.reqStats}
+}
+
+func (c *accessLogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &accessLogCore{Core: c.Core.With(fields), stats: c.stats}
+}
+
+func (c *accessLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *accessLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	for _, f := range fields {
+		if f.Key != "requestId" {
+			continue
+		}
+
+		if v, ok := c.stats.Load(f.String); ok {
+			queries, duration := v.(*queryStats).totals()
+			fields = append(fields, zap.Int("dbQueries", queries), zap.String("dbTime", duration.String()))
+		}
+
+		break
+	}
+
+	// Tee writes to all cores without level check, check again to keep
+	// per level outputs
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+
+	return nil
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
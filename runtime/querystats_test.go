@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file querystats_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import "testing"
+
+func TestQueryShape(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"none", `SELECT "u"."id" FROM "users" AS "u"`, `SELECT "u"."id" FROM "users" AS "u"`},
+		{"number", `SELECT * FROM "users" WHERE id = 42`, `SELECT * FROM "users" WHERE id = ?`},
+		{"float", `UPDATE "items" SET price = 3.14`, `UPDATE "items" SET price = ?`},
+		{"string", `SELECT * FROM "users" WHERE name = 'bob'`, `SELECT * FROM "users" WHERE name = ?`},
+		{"quoted", `SELECT * FROM "users" WHERE name = 'o''neil'`, `SELECT * FROM "users" WHERE name = ?`},
+		{"list", `SELECT * FROM "users" WHERE id IN (1, 2, 3)`, `SELECT * FROM "users" WHERE id IN (?)`},
+		{"identifier", `SELECT * FROM "t1" WHERE v2 = 7`, `SELECT * FROM "t1" WHERE v2 = ?`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := queryShape(c.query); got != c.want {
+				t.Errorf("queryShape(%q) = %q, want %q", c.query, got, c.want)
+			}
+		})
+	}
+}
+
+func TestQueryShapeRepeats(t *testing.T) {
+	s := &queryStats{shapes: make(map[string]int)}
+	s.add(`SELECT * FROM "users" WHERE id = 1`, 0)
+	s.add(`SELECT * FROM "users" WHERE id = 2`, 0)
+	s.add(`SELECT * FROM "items" WHERE id = 1`, 0)
+
+	if n, _ := s.totals(); n != 3 {
+		t.Errorf("counted %d queries, want 3", n)
+	}
+
+	if n := s.shapes[`SELECT * FROM "users" WHERE id = ?`]; n != 2 {
+		t.Errorf("counted %d repeats, want 2", n)
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
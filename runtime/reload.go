@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file reload.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"go-micro.dev/v4/config"
+	"go-micro.dev/v4/config/reader"
+	"go-micro.dev/v4/logger"
+)
+
+// ConfigChangeHandler receives new value of the watched path
+type ConfigChangeHandler func(v reader.Value)
+
+// WithConfigSource binds runtime to a go-micro config for watching
+func WithConfigSource(c config.Config) Option {
+	return func(r *Runtime) {
+		r.conf = c
+	}
+}
+
+// Reload applies settings which could be changed without restart:
+// logger level and outputs, slow query and command thresholds, session
+// expiration and CORS
+func (r *Runtime) Reload(cfg *Config) {
+	if cfg == nil {
+		return
+	}
+
+	// Logger
+	lc := cfg.Logger
+	if lc == nil {
+		lc = &configLogger{}
+	}
+
+	if lc.Debug {
+		r.zaplogger.SetLevel(logger.DebugLevel)
+	} else {
+		r.zaplogger.SetLevel(logger.InfoLevel)
+	}
+
+	r.zaplogger.SetEcho(!lc.Silence)
+	r.zaplogger.SetDisk(!lc.DiscardDisk)
+
+	// Database
+	if r.queryLog != nil && cfg.Database != nil {
+		r.queryLog.set(cfg.Database)
+	}
+
+	for name, d := range r.dbs {
+		if dc := cfg.Databases[name]; dc != nil {
+			d.queryLog.set(dc)
+		}
+	}
+
+	// Mongo
+	if r.mdbLog != nil && cfg.Mongo != nil {
+		r.mdbLog.set(cfg.Mongo)
+	}
+
+	// Session
+	if r.sessionExp != nil && cfg.Session != nil {
+		r.sessionExp.Set(sessionExpiration(cfg.Session))
+	}
+
+	// CORS
+	if r.fb != nil && cfg.Fiber != nil {
+		h, err := newCors(cfg.Fiber.Cors)
+		if err != nil {
+			r.log.Errorf("cors reload failed : %s", err.Error())
+		} else {
+			r.cors.Store(&h)
+		}
+	}
+
+	r.current.Store(cfg)
+	r.log.Info("runtime config reloaded")
+}
+
+// OnConfigChange calls fn each time value on path (dot separated) changes
+func (r *Runtime) OnConfigChange(path string, fn ConfigChangeHandler) error {
+	if r.conf == nil {
+		return errors.New("runtime has no config source")
+	}
+
+	w, err := r.conf.Watch(strings.Split(path, ".")...)
+	if err != nil {
+		return err
+	}
+
+	r.wMu.Lock()
+	r.watchers = append(r.watchers, w)
+	r.wMu.Unlock()
+
+	go func() {
+		for {
+			v, err := w.Next()
+			if err != nil {
+				r.log.Debugf("config watcher on <%s> exit : %s", path, err.Error())
+
+				return
+			}
+
+			fn(v)
+		}
+	}()
+
+	return nil
+}
+
+func (r *Runtime) watchBase() error {
+	return r.OnConfigChange(BaseConfigKey, func(v reader.Value) {
+		cfg := &Config{}
+		err := v.Scan(cfg)
+		if err != nil {
+			r.log.Errorf("scan changed config failed : %s", err.Error())
+
+			return
+		}
+
+		err = cfg.ResolveSecrets()
+		if err == nil {
+			err = cfg.Validate()
+		}
+
+		if err != nil {
+			r.log.Errorf("changed config ignored : %s", err.Error())
+
+			return
+		}
+
+		r.Reload(cfg)
+	})
+}
+
+func (r *Runtime) stopWatchers() error {
+	var errs error
+
+	r.wMu.Lock()
+	for _, w := range r.watchers {
+		errs = errors.Join(errs, w.Stop())
+	}
+
+	r.watchers = nil
+	r.wMu.Unlock()
+
+	return errs
+}
+
+// corsHandler dispatches to the current CORS handler, swapped on reload
+func (r *Runtime) corsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*r.cors.Load())(c)
+	}
+}
+
+func newCors(cfg *configCors) (h fiber.Handler, err error) {
+	if cfg == nil {
+		return cors.New(), nil
+	}
+
+	// cors.New panics on insecure or invalid settings
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%v", rec)
+		}
+	}()
+
+	h = cors.New(cors.Config{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		MaxAge:           cfg.MaxAge,
+	})
+
+	return h, nil
+}
+
+func OnConfigChange(path string, fn ConfigChangeHandler) error {
+	return defaultRuntime.OnConfigChange(path, fn)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
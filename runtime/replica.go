@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file replica.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+const (
+	ReplicaPolicyRoundRobin = "round_robin"
+	ReplicaPolicyLeastConn  = "least_conn"
+
+	DefaultReplicaCheckInterval = 5
+)
+
+type replica struct {
+	db      *bun.DB
+	healthy atomic.Bool
+}
+
+type replicaSet struct {
+	primary  *bun.DB
+	replicas []*replica
+	policy   string
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+// pick returns a healthy replica by policy, or primary if none
+func (s *replicaSet) pick() *bun.DB {
+	var healthy []*replica
+	for _, rp := range s.replicas {
+		if rp.healthy.Load() {
+			healthy = append(healthy, rp)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return s.primary
+	}
+
+	if s.policy == ReplicaPolicyLeastConn {
+		best := healthy[0]
+		for _, rp := range healthy[1:] {
+			if rp.db.Stats().InUse < best.db.Stats().InUse {
+				best = rp
+			}
+		}
+
+		return best.db
+	}
+
+	return healthy[s.next.Add(1)%uint64(len(healthy))].db
+}
+
+func (s *replicaSet) probe(ctx context.Context, rp *replica) error {
+	err := rp.db.PingContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Replay lag, postgres only. Last replay time keeps aging on an idle
+	// primary, so it only counts while received WAL is not replayed yet.
+	if s.maxLag > 0 && rp.db.Dialect().Name() == dialect.PG {
+		var lag float64
+		err = rp.db.QueryRowContext(
+			ctx,
+			`SELECT CASE
+				WHEN pg_last_wal_receive_lsn() IS NOT DISTINCT FROM pg_last_wal_replay_lsn() THEN 0
+				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+			END`,
+		).Scan(&lag)
+		if err != nil {
+			return err
+		}
+
+		if d := time.Duration(lag * float64(time.Second)); d > s.maxLag {
+			return fmt.Errorf("replication lag %s exceeds %s", d, s.maxLag)
+		}
+	}
+
+	return nil
+}
+
+func (s *replicaSet) close() error {
+	var errs error
+	for _, rp := range s.replicas {
+		errs = errors.Join(errs, rp.db.Close())
+	}
+
+	return errs
+}
+
+func (r *Runtime) initReplicas(label string, primary *bun.DB, queryLog *queryLogHook, cfg *configDatabase) (*replicaSet, error) {
+	s := &replicaSet{
+		primary: primary,
+		policy:  strings.ToLower(cfg.ReplicaPolicy),
+		maxLag:  time.Duration(cfg.ReplicaMaxLag) * time.Millisecond,
+	}
+
+	for i, dsn := range cfg.Replicas {
+		rc := *cfg
+		rc.DSN = dsn
+		tdb, err := r.openDatabase(&rc, queryLog)
+		if err != nil {
+			s.close()
+
+			return nil, fmt.Errorf("%s replica %d: %w", label, i, err)
+		}
+
+		s.replicas = append(s.replicas, &replica{db: tdb})
+	}
+
+	interval := time.Duration(cfg.ReplicaCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = DefaultReplicaCheckInterval * time.Second
+	}
+
+	check := func() {
+		for i, rp := range s.replicas {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := s.probe(ctx, rp)
+			cancel()
+
+			was := rp.healthy.Swap(err == nil)
+			if err != nil && was {
+				r.log.Warnf("%s replica %d unhealthy : %s", label, i, err.Error())
+			} else if err == nil && !was {
+				r.log.Infof("%s replica %d healthy", label, i)
+			}
+		}
+	}
+
+	check()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				check()
+			case <-r.closing:
+				return
+			}
+		}
+	}()
+
+	r.log.Infof("%s replicas <%d> initialized", label, len(s.replicas))
+
+	return s, nil
+}
+
+// DBRead returns a healthy read replica, falls back to primary DB
+func (r *Runtime) DBRead() *bun.DB {
+	if r.replicas == nil {
+		return r.db
+	}
+
+	return r.replicas.pick()
+}
+
+func DBRead() *bun.DB {
+	return defaultRuntime.DBRead()
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file retry.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+const (
+	RetryModeFailFast = "fail_fast"
+	RetryModeDegraded = "degraded"
+
+	DefaultRetryAttempts     = 1
+	DefaultRetryInitialDelay = 500
+	DefaultRetryMaxDelay     = 10000
+)
+
+func (c *configRetry) attempts() int {
+	if c == nil || c.Attempts < 1 {
+		return DefaultRetryAttempts
+	}
+
+	return c.Attempts
+}
+
+func (c *configRetry) degraded() bool {
+	return c != nil && strings.ToLower(c.Mode) == RetryModeDegraded
+}
+
+// delay returns backoff before next attempt, attempt starts from 1
+func (c *configRetry) delay(attempt int) time.Duration {
+	initial := time.Duration(DefaultRetryInitialDelay) * time.Millisecond
+	maxDelay := time.Duration(DefaultRetryMaxDelay) * time.Millisecond
+	jitter := 0.0
+	if c != nil {
+		if c.InitialDelay > 0 {
+			initial = time.Duration(c.InitialDelay) * time.Millisecond
+		}
+
+		if c.MaxDelay > 0 {
+			maxDelay = time.Duration(c.MaxDelay) * time.Millisecond
+		}
+
+		jitter = c.Jitter
+	}
+
+	d := initial
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+
+	if d > maxDelay {
+		d = maxDelay
+	}
+
+	if jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * jitter * float64(d))
+	}
+
+	return d
+}
+
+// retry calls fn until it succeeds or attempts exhausted
+func (r *Runtime) retry(name string, cfg *configRetry, fn func() error) error {
+	var (
+		err      error
+		attempts = cfg.attempts()
+	)
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if attempt < attempts {
+			d := cfg.delay(attempt)
+			r.log.Warnf("%s connect attempt %d/%d failed : %s, retry in %s", name, attempt, attempts, err.Error(), d)
+
+			select {
+			case <-time.After(d):
+			case <-r.closing:
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// reconnect keeps calling fn in background until it succeeds or runtime shuts down
+func (r *Runtime) reconnect(name string, cfg *configRetry, fn func() error, done func()) {
+	r.log.Warnf("%s started degraded, reconnecting in background", name)
+
+	go func() {
+		for attempt := 1; ; attempt++ {
+			select {
+			case <-time.After(cfg.delay(attempt)):
+			case <-r.closing:
+				return
+			}
+
+			err := fn()
+			if err == nil {
+				r.log.Infof("%s reconnected after %d attempts", name, attempt)
+				if done != nil {
+					done()
+				}
+
+				return
+			}
+
+			r.log.Debugf("%s reconnect attempt %d failed : %s", name, attempt, err.Error())
+		}
+	}()
+}
+
+// withMigrations chains migrate after ping for reconnect, component counts as
+// reconnected only after its pending migrations have run
+func (r *Runtime) withMigrations(name string, ping, migrate func() error) func() error {
+	return func() error {
+		if err := ping(); err != nil {
+			return err
+		}
+
+		if err := migrate(); err != nil {
+			r.log.Warnf("%s reachable, but migrations failed : %s", name, err.Error())
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
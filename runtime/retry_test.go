@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file retry_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go-micro.dev/v4/logger"
+)
+
+func TestReconnectMigrations(t *testing.T) {
+	r := &Runtime{log: logger.NewHelper(logger.DefaultLogger), closing: make(chan struct{})}
+	t.Cleanup(func() {
+		close(r.closing)
+	})
+
+	var pings, migrates int
+	ping := func() error {
+		pings++
+		if pings < 2 {
+			return errors.New("unreachable")
+		}
+
+		return nil
+	}
+
+	migrate := func() error {
+		migrates++
+		if migrates < 2 {
+			return errors.New("locked")
+		}
+
+		return nil
+	}
+
+	done := make(chan struct{})
+	cfg := &configRetry{InitialDelay: 1, MaxDelay: 1}
+	r.reconnect("database", cfg, r.withMigrations("database", ping, migrate), func() {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not reconnected")
+	}
+
+	// Unreachable attempt skips migrations, failed migration retries
+	if pings != 3 || migrates != 2 {
+		t.Errorf("pings %d, migrates %d, want 3 and 2", pings, migrates)
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
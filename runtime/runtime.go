@@ -17,35 +17,25 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/alexlast/bunzap"
-	brkKafka "github.com/go-micro/plugins/v4/broker/kafka"
-	brkNats "github.com/go-micro/plugins/v4/broker/nats"
-	brkRabbitmq "github.com/go-micro/plugins/v4/broker/rabbitmq"
-	chRedis "github.com/go-micro/plugins/v4/cache/redis"
 	srcConsul "github.com/go-micro/plugins/v4/config/source/consul"
-	rgConsul "github.com/go-micro/plugins/v4/registry/consul"
-	rgEtcd "github.com/go-micro/plugins/v4/registry/etcd"
-	stConsul "github.com/go-micro/plugins/v4/store/consul"
-	stRedis "github.com/go-micro/plugins/v4/store/redis"
-	redis8 "github.com/go-redis/redis/v8"
 	"github.com/gofiber/contrib/fiberzap"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/favicon"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/swagger"
 	"github.com/redis/go-redis/v9"
 	"github.com/uptrace/bun"
-	"github.com/uptrace/bun/dialect/mssqldialect"
-	"github.com/uptrace/bun/dialect/mysqldialect"
-	"github.com/uptrace/bun/dialect/pgdialect"
-	"github.com/uptrace/bun/dialect/sqlitedialect"
-	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/zenkoo-live/svc.base/middleware/session"
+	"github.com/zenkoo-live/svc.base/middleware/tenant"
+	"github.com/zenkoo-live/svc.base/utils"
 	"github.com/zenkoo-live/svc.base/zlogger"
 	"go-micro.dev/v4/broker"
 	"go-micro.dev/v4/cache"
@@ -55,24 +45,65 @@ import (
 	"go-micro.dev/v4/registry"
 	"go-micro.dev/v4/store"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
 var (
+	defaultRuntime = &Runtime{cfg: &Config{}, closing: make(chan struct{})}
+	errorHandler   fiber.ErrorHandler
+)
+
+type Runtime struct {
+	cfg          *Config
 	rg           registry.Registry
 	brk          broker.Broker
 	ch           cache.Cache
 	st           store.Store
 	db           *bun.DB
 	mdb          *mongo.Client
-	rdb          *redis.Client
+	mdbDatabase  *mongo.Database
+	mdbLog       *commandLogMonitor
+	rdb          redis.UniversalClient
 	fb           *fiber.App
 	fbAddress    string
 	zaplogger    *zlogger.Zaplog
+	log          *logger.Helper
 	env          string
 	errorHandler fiber.ErrorHandler
-)
+	healthChecks map[string]HealthCheck
+	hcMu         sync.RWMutex
+	conf         config.Config
+	watchers     []config.Watcher
+	wMu          sync.Mutex
+	queryLog     *queryLogHook
+	cors         atomic.Pointer[fiber.Handler]
+	brkConnected atomic.Bool
+	closing      chan struct{}
+	closeOnce    sync.Once
+	started      []*component
+	replicas     *replicaSet
+	dbs          map[string]*database
+	reqStats     sync.Map
+	sessionExp   *session.Expiration
+	// Latest config applied by Reload
+	current atomic.Pointer[Config]
+}
+
+type Option func(*Runtime)
+
+// WithEnv sets runtime environment name
+func WithEnv(env string) Option {
+	return func(r *Runtime) {
+		r.env = strings.ToLower(env)
+	}
+}
+
+// WithFiberErrorHandler sets error handler of fiber app
+func WithFiberErrorHandler(handler fiber.ErrorHandler) Option {
+	return func(r *Runtime) {
+		r.errorHandler = handler
+	}
+}
 
 func Init() error {
 	var (
@@ -92,6 +123,14 @@ func Init() error {
 		}
 	}
 
+	// Per-env file on top of local file
+	if p := envFilePath(cfgFilePath, strings.ToLower(config.Get("env").String(""))); p != "" {
+		err = config.Load(srcFile.NewSource(srcFile.WithPath(p)))
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
 	// From remote consul
 	if cfgConsulPrefix != "" {
 		cfgConsulAddress := config.Get("config", "consul", "address").String("")
@@ -106,11 +145,92 @@ func Init() error {
 		}
 	}
 
+	// Env and command-line overlay
+	overlay, err := overlaySource(os.Environ(), os.Args[1:])
+	if err != nil {
+		errs = errors.Join(errs, err)
+	} else if overlay != nil {
+		err = config.Load(overlay)
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
 	cfg := &Config{}
-	config.Get(BaseConfigKey).Scan(cfg)
+	err = config.Get(BaseConfigKey).Scan(cfg)
+	if err != nil {
+		return errors.Join(errs, err)
+	}
+
+	r, err := New(
+		cfg,
+		WithEnv(config.Get("env").String("")),
+		WithConfigSource(config.DefaultConfig),
+	)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if r == nil {
+		return errs
+	}
+
+	// Watch changes
+	if r.zaplogger != nil {
+		err = r.watchBase()
+		if err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	// Replace go-micro defaults
+	logger.DefaultLogger = r.zaplogger
+	if cfg.Registry != nil && r.rg != nil {
+		registry.DefaultRegistry = r.rg
+	}
+
+	if cfg.Broker != nil && r.brk != nil {
+		broker.DefaultBroker = r.brk
+	}
+
+	if cfg.Cache != nil && r.ch != nil {
+		cache.DefaultCache = r.ch
+	}
+
+	if cfg.Store != nil && r.st != nil {
+		store.DefaultStore = r.st
+	}
+
+	defaultRuntime = r
+
+	return errs
+}
+
+// New creates a runtime instance owns all components described by cfg. If
+// cfg is invalid, only the logger is created, so the error could be logged.
+func New(cfg *Config, opts ...Option) (*Runtime, error) {
+	var (
+		err, errs error
+	)
+
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	r := &Runtime{
+		cfg:          cfg,
+		errorHandler: errorHandler,
+		closing:      make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(r)
+	}
 
-	// Env
-	env = strings.ToLower(config.Get("env").String(""))
+	// Defaults of go-micro, kept if cfg is invalid
+	r.rg = registry.DefaultRegistry
+	r.brk = broker.DefaultBroker
+	r.ch = cache.DefaultCache
+	r.st = store.DefaultStore
 
 	// Logger
 	loggerOpts := []logger.Option{
@@ -134,338 +254,325 @@ func Init() error {
 		}
 	}
 
-	zaplogger, _ = zlogger.NewLogger(loggerOpts...)
-	logger.DefaultLogger = zaplogger
-
-	// Registry
-	if cfg.Registry != nil {
-		rg, err = initRegistry(cfg.Registry)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		} else {
-			registry.DefaultRegistry = rg
-		}
-	} else {
-		rg = registry.DefaultRegistry
-	}
-
-	// Broker
-	if cfg.Broker != nil {
-		brk, err = initBroker(cfg.Broker)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		} else {
-			broker.DefaultBroker = brk
-		}
-	} else {
-		brk = broker.DefaultBroker
-	}
-
-	// Cache
-	if cfg.Cache != nil {
-		ch, err = initCache(cfg.Cache)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		} else {
-			cache.DefaultCache = ch
-		}
-	} else {
-		ch = cache.DefaultCache
-	}
-
-	// Store
-	if cfg.Store != nil {
-		st, err = initStore(cfg.Store)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		} else {
-			store.DefaultStore = st
-		}
-	} else {
-		st = store.DefaultStore
-	}
-
-	// Database
-	if cfg.Database != nil {
-		db, err = initDatabase(cfg.Database)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		}
+	r.zaplogger, err = zlogger.NewLogger(loggerOpts...)
+	if err != nil {
+		return r, err
 	}
 
-	// Mongo
-	if cfg.Mongo != nil {
-		mdb, err = initMongo(cfg.Mongo)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		}
-	}
+	r.log = logger.NewHelper(r.zaplogger)
 
-	// Redis
-	if cfg.Redis != nil {
-		rdb, err = initRedis(cfg.Redis)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		}
+	err = cfg.ResolveSecrets()
+	if err != nil {
+		return r, err
 	}
 
-	// Fiber
-	if cfg.Fiber != nil {
-		fb, err = initFiber(cfg.Fiber)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		}
+	err = cfg.Validate()
+	if err != nil {
+		return r, err
 	}
 
-	// Session
-	if cfg.Session != nil {
-		err = initSession(cfg.Session)
-		if err != nil {
-			errs = errors.Join(errs, err)
-		}
-	}
+	errs = r.start()
 
-	return errs
+	return r, errs
 }
 
-func initRegistry(cfg *configRegistry) (registry.Registry, error) {
+func (r *Runtime) initRegistry(cfg *configRegistry) (registry.Registry, error) {
 	if cfg == nil {
 		return nil, errors.New("empty registry configuration")
 	}
 
-	var trg registry.Registry
+	factory, err := registryDrivers.get(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
 
-	switch strings.ToLower(cfg.Driver) {
-	case "etcd":
-		// ETCD v3
-		trg = rgEtcd.NewRegistry(
-			registry.Addrs(cfg.Address...),
-		)
-	default:
-		// Consul
-		trg = rgConsul.NewRegistry(
-			registry.Addrs(cfg.Address...),
-		)
+	trg, err := factory(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Infof("registry <%s> initialized", cfg.Driver)
+	r.log.Infof("registry <%s> initialized", cfg.Driver)
 
 	return trg, nil
 }
 
-func initBroker(cfg *configBroker) (broker.Broker, error) {
+func (r *Runtime) initBroker(cfg *configBroker) (broker.Broker, error) {
 	if cfg == nil {
 		return nil, errors.New("empty broker configuration")
 	}
 
-	var tbrk broker.Broker
+	factory, err := brokerDrivers.get(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
 
-	switch strings.ToLower(cfg.Driver) {
-	case "kafka":
-		// Kafka
-		tbrk = brkKafka.NewBroker(
-			broker.Addrs(cfg.Address...),
-		)
-	case "rabbitmq":
-		tbrk = brkRabbitmq.NewBroker(
-			broker.Addrs(cfg.Address...),
-		)
-	default:
-		// Nats
-		tbrk = brkNats.NewBroker(
-			broker.Addrs(cfg.Address...),
-		)
+	tbrk, err := factory(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := tbrk.Init(); err != nil {
-		logger.Fatalf("Broker Init error: %v", err)
-
-		return nil, err
+		return nil, fmt.Errorf("broker init failed: %w", err)
 	}
 
-	if err := tbrk.Connect(); err != nil {
-		logger.Fatalf("Broker Connect error: %v", err)
+	err = r.retry("broker", cfg.Retry, tbrk.Connect)
+	if err != nil {
+		if !cfg.Retry.degraded() {
+			return nil, fmt.Errorf("broker connect failed: %w", err)
+		}
 
-		return nil, err
+		r.reconnect("broker", cfg.Retry, tbrk.Connect, func() {
+			r.brkConnected.Store(true)
+		})
+	} else {
+		r.brkConnected.Store(true)
 	}
 
-	logger.Infof("broker <%s> initialized", cfg.Driver)
+	r.log.Infof("broker <%s> initialized", cfg.Driver)
 
 	return tbrk, nil
 }
 
-func initCache(cfg *configCache) (cache.Cache, error) {
+func (r *Runtime) initCache(cfg *configCache) (cache.Cache, error) {
 	if cfg == nil {
 		return nil, errors.New("empty cache configuration")
 	}
 
-	var tch cache.Cache
-
-	switch strings.ToLower(cfg.Driver) {
-	case "memory":
-	case "redis":
-		// Redis
-		tch = chRedis.NewCache(
-			chRedis.WithRedisOptions(
-				redis8.UniversalOptions{
-					Addrs:    cfg.Address,
-					DB:       cfg.DB,
-					Password: cfg.Password,
-				},
-			),
-		)
-	default:
-		tch = cache.DefaultCache
+	factory, err := cacheDrivers.get(cfg.Driver)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Infof("cache <%s> initialized", cfg.Driver)
+	tch, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log.Infof("cache <%s> initialized", cfg.Driver)
 
 	return tch, nil
 }
 
-func initStore(cfg *configStore) (store.Store, error) {
+func (r *Runtime) initStore(cfg *configStore) (store.Store, error) {
 	if cfg == nil {
 		return nil, errors.New("empty store configuration")
 	}
 
-	var tst store.Store
-
-	switch strings.ToLower(cfg.Driver) {
-	case "consul":
-		// Consul
-		// TODO: DO NOT USE ME
-		tst = stConsul.NewStore()
-	case "redis":
-		// Redis
-		tst = stRedis.NewStore(
-			stRedis.WithRedisOptions(
-				redis8.UniversalOptions{
-					Addrs:    cfg.Address,
-					DB:       cfg.DB,
-					Password: cfg.Password,
-				},
-			),
-		)
-	default:
-		tst = store.DefaultStore
+	factory, err := storeDrivers.get(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	tst, err := factory(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Infof("store <%s> initialized", cfg.Driver)
+	r.log.Infof("store <%s> initialized", cfg.Driver)
 
 	return tst, nil
 }
 
-func initDatabase(cfg *configDatabase) (*bun.DB, error) {
+func (r *Runtime) initDatabase(name string, cfg *configDatabase) (*database, error) {
 	if cfg == nil {
 		return nil, errors.New("empty database configuration")
 	}
 
-	var (
-		sqldb *sql.DB
-		err   error
-		tdb   *bun.DB
-	)
+	label := "database"
+	if name != "" {
+		label = "database " + name
+	}
+
+	d := &database{
+		queryLog: newQueryLogHook(r.zaplogger.Zap(), cfg),
+	}
+
+	tdb, err := r.openDatabase(cfg, d.queryLog)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replicas take no writes to audit
+	if r.cfg.Audit != nil {
+		tdb.AddQueryHook(r.auditHook(r.cfg.Audit))
+	}
+
+	mname := name
+	if mname == "" {
+		mname = DefaultDatabaseName
+	}
+
+	migrate := func() error {
+		return r.migrate(label, mname, cfg, tdb)
+	}
+
+	err = r.retry(label, cfg.Retry, tdb.Ping)
+	if err != nil {
+		if !cfg.Retry.degraded() {
+			tdb.Close()
 
-	switch strings.ToLower(cfg.Driver) {
-	case "mysql":
-		// MySQL
-		sqldb, err = sql.Open("mysql", cfg.DSN)
-		if err != nil {
 			return nil, err
 		}
 
-		tdb = bun.NewDB(sqldb, mysqldialect.New())
-	case "mssql":
-		// MS-SQLServer
-		sqldb, err = sql.Open("sqlserver", cfg.DSN)
+		// Pending migrations run once reachable
+		r.reconnect(label, cfg.Retry, r.withMigrations(label, tdb.Ping, migrate), nil)
+	} else {
+		err = migrate()
 		if err != nil {
+			tdb.Close()
+
 			return nil, err
 		}
+	}
 
-		tdb = bun.NewDB(sqldb, mssqldialect.New())
-	case "sqlite":
-		// SQLite
-		sqldb, err = sql.Open("sqlite3", cfg.DSN)
+	d.db = tdb
+	if len(cfg.Replicas) > 0 {
+		d.replicas, err = r.initReplicas(label, tdb, d.queryLog, cfg)
 		if err != nil {
+			tdb.Close()
+
 			return nil, err
 		}
+	}
+
+	r.log.Infof("%s <%s> initialized", label, cfg.Driver)
 
-		tdb = bun.NewDB(sqldb, sqlitedialect.New())
-	default:
-		// PostgreSQL
-		sqldb = sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.DSN)))
-		tdb = bun.NewDB(sqldb, pgdialect.New())
+	return d, nil
+}
+
+// openDatabase creates bun DB with pool settings and hooks, without ping
+func (r *Runtime) openDatabase(cfg *configDatabase, queryLog *queryLogHook) (*bun.DB, error) {
+	factory, err := databaseDrivers.get(cfg.Driver)
+	if err != nil {
+		return nil, err
 	}
 
-	err = tdb.Ping()
+	tdb, err := factory(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	if cfg.Debug {
-		tdb.AddQueryHook(bunzap.NewQueryHook(bunzap.QueryHookOptions{
-			Logger: zaplogger.Zap(),
-		}))
-	} else if cfg.SlowQueryDuration > 0 {
-		tdb.AddQueryHook(bunzap.NewQueryHook(bunzap.QueryHookOptions{
-			Logger:       zaplogger.Zap(),
-			SlowDuration: time.Duration(cfg.SlowQueryDuration) * time.Millisecond,
-		}))
+	// Pool
+	if cfg.MaxOpenConns != 0 {
+		tdb.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+
+	if cfg.MaxIdleConns != 0 {
+		tdb.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+
+	if cfg.ConnMaxLifetime > 0 {
+		tdb.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	}
+
+	if cfg.ConnMaxIdleTime > 0 {
+		tdb.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
 	}
 
-	logger.Infof("database <%s> initialized", cfg.Driver)
+	tdb.AddQueryHook(queryLog)
+	tdb.AddQueryHook(queryStatsHook{})
 
 	return tdb, nil
 }
 
-func initMongo(cfg *configMongo) (*mongo.Client, error) {
+func (r *Runtime) initMongo(cfg *configMongo) (*mongo.Client, error) {
 	if cfg == nil {
 		return nil, errors.New("empty mongo configuration")
 	}
 
-	var (
-		err error
-		tdb *mongo.Client
-	)
+	opts, err := mongoOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mdbLog = newCommandLogMonitor(r.zaplogger.Zap(), cfg)
+	opts.SetMonitor(r.mdbLog.monitor())
 
-	tdb, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(cfg.DSN))
+	tdb, err := mongo.Connect(context.Background(), opts)
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Infof("mongodb initialized")
+	// Connect does not reach servers, ping is bounded by server selection
+	// timeout
+	ping := func() error {
+		return tdb.Ping(context.Background(), nil)
+	}
+	var mdb *mongo.Database
+	if name := mongoDatabaseName(cfg); name != "" {
+		mdb = tdb.Database(name)
+	}
+
+	migrate := func() error {
+		return r.migrateMongo(cfg, mdb)
+	}
+
+	err = r.retry("mongo", cfg.Retry, ping)
+	if err != nil {
+		if !cfg.Retry.degraded() {
+			tdb.Disconnect(context.Background())
+
+			return nil, err
+		}
+
+		// Pending migrations run once reachable
+		r.reconnect("mongo", cfg.Retry, r.withMigrations("mongo", ping, migrate), nil)
+	} else {
+		err = migrate()
+		if err != nil {
+			tdb.Disconnect(context.Background())
+
+			return nil, err
+		}
+	}
+
+	r.mdbDatabase = mdb
+
+	r.log.Infof("mongodb initialized")
 
 	return tdb, nil
 }
 
-func initRedis(cfg *configRedis) (*redis.Client, error) {
+func (r *Runtime) initRedis(cfg *configRedis) (redis.UniversalClient, error) {
 	if cfg == nil {
 		return nil, errors.New("empty redis configuration")
 	}
 
 	var (
 		err error
-		tdb *redis.Client
+		tdb redis.UniversalClient
 	)
 
-	tdb = redis.NewClient(&redis.Options{
-		Addr:       cfg.Address,
-		Password:   cfg.Password,
-		DB:         cfg.DB,
-		PoolSize:   cfg.PoolSize,
-		MaxRetries: cfg.MaxRetries,
+	// Failover client if master name set, cluster client if more addresses
+	tdb = redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs:          cfg.Address,
+		MasterName:     cfg.MasterName,
+		RouteByLatency: cfg.RouteByLatency,
+		Password:       cfg.Password.Value(),
+		DB:             cfg.DB,
+		PoolSize:       cfg.PoolSize,
+		MaxRetries:     cfg.MaxRetries,
 	})
-	_, err = tdb.Ping(context.TODO()).Result()
+	ping := func() error {
+		return tdb.Ping(context.TODO()).Err()
+	}
+	err = r.retry("redis", cfg.Retry, ping)
 	if err != nil {
-		return nil, err
+		if !cfg.Retry.degraded() {
+			tdb.Close()
+
+			return nil, err
+		}
+
+		r.reconnect("redis", cfg.Retry, ping, nil)
 	}
 
-	logger.Infof("redis initialized")
+	r.log.Infof("redis initialized")
 
 	return tdb, nil
 }
 
-func initFiber(cfg *configFiber) (*fiber.App, error) {
+func (r *Runtime) initFiber(cfg *configFiber) (*fiber.App, error) {
 	if cfg == nil {
 		return nil, errors.New("empty fiber configuration")
 	}
@@ -482,7 +589,7 @@ func initFiber(cfg *configFiber) (*fiber.App, error) {
 		Concurrency:           cfg.Concurrency,
 		ReadBufferSize:        cfg.ReadBufferSize,
 		WriteBufferSize:       cfg.WriteBufferSize,
-		ErrorHandler:          errorHandler,
+		ErrorHandler:          r.errorHandler,
 	}
 	tfb := fiber.New(fc)
 	if cfg.EnableStackTrace {
@@ -497,13 +604,35 @@ func initFiber(cfg *configFiber) (*fiber.App, error) {
 		))
 	}
 
+	ch, err := newCors(cfg.Cors)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cors.Store(&ch)
 	tfb.Use(
-		cors.New(),
+		r.corsHandler(),
 		favicon.New(),
 		requestid.New(),
+		func(c *fiber.Ctx) error {
+			if rid, ok := c.Locals("requestid").(string); ok {
+				c.SetUserContext(utils.WithRequestID(c.UserContext(), rid))
+			}
+
+			return c.Next()
+		},
+	)
+
+	accessLogger := r.zaplogger.Zap()
+	if cfg.QueryStats != nil {
+		tfb.Use(r.queryStatsHandler(cfg.QueryStats))
+		accessLogger = accessLogger.WithOptions(zap.WrapCore(r.wrapAccessLogCore))
+	}
+
+	tfb.Use(
 		fiberzap.New(
 			fiberzap.Config{
-				Logger: zaplogger.Zap(),
+				Logger: accessLogger,
 				Fields: []string{"latency", "status", "method", "url", "ip", "requestId", "error"},
 			},
 		),
@@ -513,117 +642,253 @@ func initFiber(cfg *configFiber) (*fiber.App, error) {
 		tfb.All("/docs/*", swagger.New(swagger.ConfigDefault))
 	}
 
+	if cfg.EnableHealthCheck {
+		r.mountHealthCheck(tfb, cfg)
+	}
+
 	if cfg.Address != "" {
-		fbAddress = cfg.Address
+		r.fbAddress = cfg.Address
 	} else {
-		fbAddress = DefaultHTTPAdvertiseAddr
+		r.fbAddress = DefaultHTTPAdvertiseAddr
 	}
 
 	return tfb, nil
 }
 
-func initSession(cfg *configSession) error {
+func (r *Runtime) initSession(cfg *configSession) error {
 	if cfg == nil {
 		return errors.New("empty session configuration")
 	}
 
-	if fb != nil {
-		r := rdb
-		if r == nil {
-			// TODO: create new redis client from config
-			r = redis.NewClient(&redis.Options{})
-		}
+	if r.fb == nil || r.rdb == nil {
+		return errors.New("session requires fiber and redis")
+	}
 
-		mw := session.New(
-			session.Config{
-				IDSource:   cfg.IDSource,
-				IDKey:      cfg.IDKey,
-				IDPrefix:   cfg.IDPrefix,
-				Expiration: time.Second * time.Duration(cfg.Expiration),
-				Storage:    r,
-			},
-		)
-		fb.Use(mw)
+	// Any other source used to mean header
+	src := strings.ToLower(cfg.IDSource)
+	if src != "" && src != "cookie" && src != "header" {
+		r.log.Warnf("unknown session id_source <%s>, using header", cfg.IDSource)
+		src = "header"
+	}
+
+	r.sessionExp = session.NewExpiration(sessionExpiration(cfg))
+	mw := session.New(
+		session.Config{
+			IDSource:         src,
+			IDKey:            cfg.IDKey,
+			IDPrefix:         cfg.IDPrefix,
+			Expiration:       sessionExpiration(cfg),
+			ExpirationHandle: r.sessionExp,
+			ActorKey:         cfg.ActorKey,
+			Storage:          r.rdb,
+		},
+	)
+	r.fb.Use(mw)
+
+	r.log.Info("fiber session initialized")
+
+	return nil
+}
+
+// sessionExpiration returns expiration of cfg, default of session middleware
+// if unset
+func sessionExpiration(cfg *configSession) time.Duration {
+	if cfg.Expiration > 0 {
+		return time.Second * time.Duration(cfg.Expiration)
 	}
 
-	logger.Info("fiber session initialized")
+	return session.ConfigDefault.Expiration
+}
+
+func (r *Runtime) initTenant(cfg *configTenant) error {
+	if cfg == nil {
+		return errors.New("empty tenant configuration")
+	}
+
+	if r.fb == nil {
+		return errors.New("tenant requires fiber")
+	}
+
+	r.fb.Use(tenant.New(
+		tenant.Config{
+			Source: cfg.Source,
+			Key:    cfg.Key,
+			Strict: cfg.Strict,
+		},
+	))
+
+	r.log.Info("fiber tenant initialized")
 
 	return nil
 }
 
+// Config returns config last applied by Reload. Components keep settings
+// they were created with, except the ones Reload changes.
+func (r *Runtime) Config() *Config {
+	if cfg := r.current.Load(); cfg != nil {
+		return cfg
+	}
+
+	return r.cfg
+}
+
+func (r *Runtime) Registry() registry.Registry {
+	return r.rg
+}
+
+func (r *Runtime) Broker() broker.Broker {
+	return r.brk
+}
+
+func (r *Runtime) Cache() cache.Cache {
+	return r.ch
+}
+
+func (r *Runtime) Store() store.Store {
+	return r.st
+}
+
+func (r *Runtime) DB() *bun.DB {
+	return r.db
+}
+
+// DBStats returns pool statistics of DB, zero if no database configured
+func (r *Runtime) DBStats() sql.DBStats {
+	if r.db == nil {
+		return sql.DBStats{}
+	}
+
+	return r.db.Stats()
+}
+
+func (r *Runtime) Mongo() *mongo.Client {
+	return r.mdb
+}
+
+// MongoDB returns default database of mongo section, nil if neither
+// database nor DSN names one
+func (r *Runtime) MongoDB() *mongo.Database {
+	return r.mdbDatabase
+}
+
+func (r *Runtime) Redis() redis.UniversalClient {
+	return r.rdb
+}
+
+func (r *Runtime) Fiber() *fiber.App {
+	return r.fb
+}
+
+func (r *Runtime) Logger() logger.Logger {
+	return r.zaplogger
+}
+
+func (r *Runtime) Zap() *zap.Logger {
+	return r.zaplogger.Zap()
+}
+
+func (r *Runtime) Env() string {
+	return r.env
+}
+
+func (r *Runtime) AppendEnv() string {
+	if r.env != "" {
+		return "::" + r.env
+	}
+
+	return ""
+}
+
+func (r *Runtime) StartHTTP() {
+	if r.fb != nil {
+		go func() {
+			r.log.Infof("fiber listening on %s", r.fbAddress)
+			err := r.fb.Listen(r.fbAddress)
+			if err != nil {
+				r.log.Errorf("fiber listen failed on %s : %s", r.fbAddress, err.Error())
+
+				return
+			}
+
+			r.log.Infof("fiber closed")
+		}()
+	}
+}
+
+func (r *Runtime) StopHTTP() {
+	if r.fb != nil {
+		r.log.Info("stopping fiber server")
+		r.fb.Shutdown()
+	}
+}
+
+// Default returns the runtime instance created by Init
+func Default() *Runtime {
+	return defaultRuntime
+}
+
 func Registry() registry.Registry {
-	return rg
+	return defaultRuntime.Registry()
 }
 
 func Broker() broker.Broker {
-	return brk
+	return defaultRuntime.Broker()
 }
 
 func Cache() cache.Cache {
-	return ch
+	return defaultRuntime.Cache()
 }
 
 func Store() store.Store {
-	return st
+	return defaultRuntime.Store()
 }
 
 func DB() *bun.DB {
-	return db
+	return defaultRuntime.DB()
+}
+
+func DBStats() sql.DBStats {
+	return defaultRuntime.DBStats()
 }
 
 func Mongo() *mongo.Client {
-	return mdb
+	return defaultRuntime.Mongo()
 }
 
-func Redis() *redis.Client {
-	return rdb
+func MongoDB() *mongo.Database {
+	return defaultRuntime.MongoDB()
+}
+
+func Redis() redis.UniversalClient {
+	return defaultRuntime.Redis()
 }
 
 func Fiber() *fiber.App {
-	return fb
+	return defaultRuntime.Fiber()
 }
 
 func Logger() logger.Logger {
-	return zaplogger
+	return defaultRuntime.Logger()
 }
 
 func Zap() *zap.Logger {
-	return zaplogger.Zap()
+	return defaultRuntime.Zap()
 }
 
 func Env() string {
-	return env
+	return defaultRuntime.Env()
 }
 
 func AppendEnv() string {
-	if env != "" {
-		return "::" + env
-	}
-
-	return ""
+	return defaultRuntime.AppendEnv()
 }
 
 func StartHTTP() {
-	if fb != nil {
-		go func() {
-			logger.Infof("fiber listening on %s", fbAddress)
-			err := fb.Listen(fbAddress)
-			if err != nil {
-				logger.Errorf("fiber listen failed on %s : %s", fbAddress, err.Error())
-
-				return
-			}
-
-			logger.Infof("fiber closed")
-		}()
-	}
+	defaultRuntime.StartHTTP()
 }
 
 func StopHTTP() {
-	if fb != nil {
-		logger.Info("stopping fiber server")
-		fb.Shutdown()
-	}
+	defaultRuntime.StopHTTP()
 }
 
 func SetFiberErrorHandler(handler fiber.ErrorHandler) {
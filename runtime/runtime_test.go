@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file runtime_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"go-micro.dev/v4/broker"
+	"go-micro.dev/v4/cache"
+	"go-micro.dev/v4/logger"
+	"go-micro.dev/v4/registry"
+	"go-micro.dev/v4/store"
+)
+
+func TestNewInvalid(t *testing.T) {
+	cfg := testConfig(t, `{"logger": {"silence": true, "discard_disk": true}, "database": {"driver": "oracle"}}`)
+	r, err := New(cfg)
+	if err == nil {
+		t.Fatal("invalid config accepted")
+	}
+
+	if r.Logger() == nil {
+		t.Error("no logger for the error")
+	}
+
+	if r.Registry() != registry.DefaultRegistry || r.Broker() != broker.DefaultBroker ||
+		r.Cache() != cache.DefaultCache || r.Store() != store.DefaultStore {
+		t.Error("go-micro defaults not kept")
+	}
+}
+
+func TestShutdownDefaultLogger(t *testing.T) {
+	saved := logger.DefaultLogger
+	t.Cleanup(func() {
+		logger.DefaultLogger = saved
+	})
+
+	r, err := New(testConfig(t, `{"logger": {"silence": true, "discard_disk": true}}`))
+	if err != nil {
+		t.Fatalf("new: %s", err)
+	}
+
+	logger.DefaultLogger = r.zaplogger
+	err = r.Shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("shutdown: %s", err)
+	}
+
+	if logger.DefaultLogger == r.zaplogger {
+		t.Error("default logger still closed zap logger")
+	}
+
+	// Must not write to closed rolling files
+	logger.DefaultLogger.Log(logger.InfoLevel, "after shutdown")
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
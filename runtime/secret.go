@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file secret.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	secretMask = "******"
+)
+
+// Secret is a config value which may hold references like ${env:DB_PASSWORD}
+// or ${file:/run/secrets/db}. It is masked in logs and dumps, use Value to
+// get the resolved plain text.
+type Secret string
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+
+	return secretMask
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// SecretResolver looks up a reference in a secret backend
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+type SecretResolverFunc func(ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	secretRefPattern = regexp.MustCompile(`\$\{([a-zA-Z0-9_\-]+):([^}]*)\}`)
+	secretResolvers  = struct {
+		sync.RWMutex
+		m map[string]SecretResolver
+	}{
+		m: make(map[string]SecretResolver),
+	}
+)
+
+// RegisterSecretResolver adds a backend used by ${scheme:ref} references
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolvers.Lock()
+	secretResolvers.m[strings.ToLower(scheme)] = resolver
+	secretResolvers.Unlock()
+}
+
+func resolveSecret(s Secret) (Secret, error) {
+	var errs error
+
+	v := secretRefPattern.ReplaceAllStringFunc(string(s), func(m string) string {
+		parts := secretRefPattern.FindStringSubmatch(m)
+		scheme := strings.ToLower(parts[1])
+		secretResolvers.RLock()
+		resolver, ok := secretResolvers.m[scheme]
+		secretResolvers.RUnlock()
+		if !ok {
+			errs = errors.Join(errs, fmt.Errorf("unknown secret scheme <%s>", scheme))
+
+			return m
+		}
+
+		// Never put the resolved value into error
+		v, err := resolver.Resolve(parts[2])
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("resolve secret <%s:%s> failed: %w", scheme, parts[2], err))
+
+			return m
+		}
+
+		return v
+	})
+
+	return Secret(v), errs
+}
+
+// resolveSecrets replaces references in every Secret field of v
+func resolveSecrets(v reflect.Value, path string) error {
+	var errs error
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			errs = resolveSecrets(v.Elem(), path)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = t.Field(i).Name
+			}
+
+			errs = errors.Join(errs, resolveSecrets(v.Field(i), path+"."+name))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = errors.Join(errs, resolveSecrets(v.Index(i), fmt.Sprintf("%s[%d]", path, i)))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			errs = errors.Join(errs, resolveSecrets(iter.Value(), fmt.Sprintf("%s.%v", path, iter.Key())))
+		}
+	case reflect.String:
+		if v.Type() == reflect.TypeOf(Secret("")) && v.CanSet() {
+			s, err := resolveSecret(Secret(v.String()))
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			v.SetString(string(s))
+		}
+	}
+
+	return errs
+}
+
+// ResolveSecrets replaces secret references in config with resolved values
+func (c *Config) ResolveSecrets() error {
+	return resolveSecrets(reflect.ValueOf(c), BaseConfigKey)
+}
+
+func init() {
+	RegisterSecretResolver("env", SecretResolverFunc(func(ref string) (string, error) {
+		v, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s not set", ref)
+		}
+
+		return v, nil
+	}))
+	RegisterSecretResolver("file", SecretResolverFunc(func(ref string) (string, error) {
+		b, err := os.ReadFile(ref)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}))
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
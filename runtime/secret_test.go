@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file secret_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "s3cret")
+	file := filepath.Join(t.TempDir(), "dsn")
+	err := os.WriteFile(file, []byte("postgres://u@h/db\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(t, `{
+		"database": {"dsn": "${file:`+file+`}"},
+		"databases": {"legacy": {"dsn": "mysql://u:${env:TEST_DB_PASSWORD}@h/db", "replicas": ["${env:TEST_DB_PASSWORD}"]}},
+		"redis": {"password": "plain"}
+	}`)
+	err = cfg.ResolveSecrets()
+	if err != nil {
+		t.Fatalf("resolve: %s", err)
+	}
+
+	if v := cfg.Database.DSN.Value(); v != "postgres://u@h/db" {
+		t.Errorf("file secret resolved to %q", v)
+	}
+
+	if v := cfg.Databases["legacy"].DSN.Value(); v != "mysql://u:s3cret@h/db" {
+		t.Errorf("env secret resolved to %q", v)
+	}
+
+	if v := cfg.Databases["legacy"].Replicas[0].Value(); v != "s3cret" {
+		t.Errorf("secret in list resolved to %q", v)
+	}
+
+	if v := cfg.Redis.Password.Value(); v != "plain" {
+		t.Errorf("plain secret changed to %q", v)
+	}
+
+	// Masked in dumps
+	b, _ := json.Marshal(cfg.Database)
+	if strings.Contains(string(b), "postgres://") {
+		t.Errorf("secret leaked in %s", b)
+	}
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unknown scheme",
+			src:  `{"database": {"dsn": "${vault:db}"}}`,
+			want: "base.database.dsn: unknown secret scheme <vault>",
+		},
+		{
+			name: "missing env",
+			src:  `{"redis": {"password": "${env:TEST_NOT_SET_ANYWHERE}"}}`,
+			want: "base.redis.password: resolve secret <env:TEST_NOT_SET_ANYWHERE> failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testConfig(t, tt.src).ResolveSecrets()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file session_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/zenkoo-live/svc.base/middleware/session"
+	"go-micro.dev/v4/logger"
+)
+
+func TestSessionExpiration(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiration int
+		want       time.Duration
+	}{
+		{"unset", 0, session.ConfigDefault.Expiration},
+		{"negative", -1, session.ConfigDefault.Expiration},
+		{"set", 60, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rdb := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{"127.0.0.1:0"}})
+			t.Cleanup(func() {
+				rdb.Close()
+			})
+
+			r := &Runtime{fb: fiber.New(), rdb: rdb, log: logger.NewHelper(logger.DefaultLogger)}
+			err := r.initSession(&configSession{Expiration: tt.expiration})
+			if err != nil {
+				t.Fatalf("init session: %s", err)
+			}
+
+			if got := r.sessionExp.Get(); got != tt.want {
+				t.Errorf("expiration %s, want %s", got, tt.want)
+			}
+
+			// Reload with expiration removed falls back to default
+			r.sessionExp.Set(sessionExpiration(&configSession{}))
+			if got := r.sessionExp.Get(); got != session.ConfigDefault.Expiration {
+				t.Errorf("reloaded expiration %s, want default", got)
+			}
+		})
+	}
+}
+
+func TestSessionIDSource(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", "cookie"},
+		{"Cookie", "cookie"},
+		{"header", "header"},
+		{"query", "header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			rdb := redis.NewUniversalClient(&redis.UniversalOptions{Addrs: []string{"127.0.0.1:0"}})
+			t.Cleanup(func() {
+				rdb.Close()
+			})
+
+			r := &Runtime{fb: fiber.New(), rdb: rdb, log: logger.NewHelper(logger.DefaultLogger)}
+			err := r.initSession(&configSession{IDSource: tt.src})
+			if err != nil {
+				t.Fatalf("init session: %s", err)
+			}
+
+			if session.IDSource != tt.want {
+				t.Errorf("id source %q, want %q", session.IDSource, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file shutdown.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-micro.dev/v4/logger"
+)
+
+const (
+	DefaultShutdownTimeout = 30 * time.Second
+)
+
+// Shutdown tears down components in reverse order of initialization.
+// Steps still running when ctx is done are abandoned and reported as errors,
+// the logger is always flushed at last.
+func (r *Runtime) Shutdown(ctx context.Context) error {
+	r.closeOnce.Do(func() {
+		if r.closing != nil {
+			close(r.closing)
+		}
+	})
+
+	errs := r.stopWatchers()
+
+	for i := len(r.started) - 1; i >= 0; i-- {
+		c := r.started[i]
+		if c.close == nil {
+			continue
+		}
+
+		errs = errors.Join(errs, shutdownStep(ctx, c.name, func(ctx context.Context) error {
+			return c.close(ctx, r)
+		}))
+	}
+
+	r.started = nil
+
+	// No logger if Init never called
+	if r.log != nil {
+		if errs != nil {
+			r.log.Errorf("runtime shutdown with errors : %s", errs.Error())
+		} else {
+			r.log.Info("runtime shutdown")
+		}
+	}
+
+	if r.zaplogger != nil {
+		// Later logs of go-micro go to its own logger
+		if logger.DefaultLogger == r.zaplogger {
+			logger.DefaultLogger = logger.NewLogger()
+		}
+
+		errs = errors.Join(errs, r.zaplogger.Close())
+	}
+
+	return errs
+}
+
+// ShutdownOnSignal blocks until SIGINT or SIGTERM received, then shuts the
+// runtime down within timeout
+func (r *Runtime) ShutdownOnSignal(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(ch)
+
+	sig := <-ch
+	if r.log != nil {
+		r.log.Infof("signal <%s> received", sig.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return r.Shutdown(ctx)
+}
+
+func Shutdown(ctx context.Context) error {
+	return defaultRuntime.Shutdown(ctx)
+}
+
+func ShutdownOnSignal(timeout time.Duration) error {
+	return defaultRuntime.ShutdownOnSignal(timeout)
+}
+
+func shutdownStep(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", name, ctx.Err())
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
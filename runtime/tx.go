@@ -0,0 +1,189 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file tx.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/zenkoo-live/svc.base/audit"
+)
+
+const (
+	DefaultTxRetryAttempts     = 3
+	DefaultTxRetryInitialDelay = 50
+	DefaultTxRetryMaxDelay     = 1000
+)
+
+// txKey holds transaction of database name in context
+type txKey struct {
+	name string
+}
+
+var defaultTxRetry = &configRetry{
+	Attempts:     DefaultTxRetryAttempts,
+	InitialDelay: DefaultTxRetryInitialDelay,
+	MaxDelay:     DefaultTxRetryMaxDelay,
+	Jitter:       0.2,
+}
+
+// retryableTxError reports deadlock or serialization failure, the whole
+// transaction could be replayed
+func retryableTxError(err error) bool {
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		code := pgErr.Field('C')
+
+		return code == "40001" || code == "40P01"
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return myErr.Number == 1213
+	}
+
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		return msErr.Number == 1205
+	}
+
+	return false
+}
+
+// RunInTx runs fn in a transaction stored in ctx, see IDB. Called inside
+// another transaction, fn runs in a savepoint and errors go to the outer one.
+// The outermost transaction is replayed on deadlock or serialization failure,
+// so fn should have no side effects besides database. Audit records failed
+// by strict audit roll back the transaction, records for broker are
+// published after commit.
+func (r *Runtime) RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return r.RunInTxNamed(ctx, DefaultDatabaseName, opts, fn)
+}
+
+// RunInTxNamed is RunInTx on database of DBNamed, with tx_retry of that
+// database. Transactions of different databases are kept apart in ctx.
+func (r *Runtime) RunInTxNamed(ctx context.Context, name string, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error {
+	key := txKey{name: name}
+	if tx, ok := ctx.Value(key).(bun.Tx); ok {
+		var spCtx context.Context
+		err := tx.RunInTx(ctx, opts, func(ctx context.Context, sp bun.Tx) error {
+			spCtx = audit.Defer(context.WithValue(ctx, key, sp))
+
+			return fn(spCtx, sp)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Hand audit records to the outer transaction
+		return audit.Flush(spCtx)
+	}
+
+	db := r.DBNamed(name)
+	if db == nil {
+		return fmt.Errorf("database <%s> not initialized", name)
+	}
+
+	cfg := defaultTxRetry
+	if dc := r.databaseConfig(name); dc != nil && dc.TxRetry != nil {
+		cfg = dc.TxRetry
+	}
+
+	attempts := cfg.attempts()
+	for attempt := 1; ; attempt++ {
+		var txCtx context.Context
+		err := db.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+			txCtx = audit.Defer(audit.WithErrors(context.WithValue(ctx, key, tx)))
+			err := fn(txCtx, tx)
+			if err != nil {
+				return err
+			}
+
+			return audit.Errors(txCtx)
+		})
+		if err == nil {
+			// Committed, could not be undone by audit failures
+			ferr := audit.Flush(txCtx)
+			if ferr != nil {
+				r.log.Errorf("publish audit records of database <%s> failed : %s", name, ferr.Error())
+			}
+
+			return nil
+		}
+
+		if attempt >= attempts || !retryableTxError(err) {
+			return err
+		}
+
+		d := cfg.delay(attempt)
+		r.log.Warnf("transaction attempt %d/%d on database <%s> failed : %s, retry in %s", attempt, attempts, name, err.Error(), d)
+
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// IDB returns transaction of RunInTx in ctx, or DB() outside of it
+func (r *Runtime) IDB(ctx context.Context) bun.IDB {
+	return r.IDBNamed(ctx, DefaultDatabaseName)
+}
+
+// IDBNamed returns transaction of RunInTxNamed in ctx, or DBNamed(name)
+// outside of it
+func (r *Runtime) IDBNamed(ctx context.Context, name string) bun.IDB {
+	if tx, ok := ctx.Value(txKey{name: name}).(bun.Tx); ok {
+		return tx
+	}
+
+	if db := r.DBNamed(name); db != nil {
+		return db
+	}
+
+	return nil
+}
+
+func RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return defaultRuntime.RunInTx(ctx, opts, fn)
+}
+
+func RunInTxNamed(ctx context.Context, name string, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error {
+	return defaultRuntime.RunInTxNamed(ctx, name, opts, fn)
+}
+
+func IDB(ctx context.Context) bun.IDB {
+	return defaultRuntime.IDB(ctx)
+}
+
+func IDBNamed(ctx context.Context, name string) bun.IDB {
+	return defaultRuntime.IDBNamed(ctx, name)
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
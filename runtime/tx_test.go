@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file tx_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/internal/testdb"
+	"go-micro.dev/v4/logger"
+)
+
+func testRuntimeDB(t *testing.T, cfg *Config) *Runtime {
+	t.Helper()
+
+	db := testdb.SQLite(t)
+	_, err := db.ExecContext(context.Background(), "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)")
+	if err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	return &Runtime{cfg: cfg, db: db, log: logger.NewHelper(logger.DefaultLogger)}
+}
+
+func countItems(t *testing.T, db bun.IDB) int {
+	t.Helper()
+
+	n, err := db.NewSelect().TableExpr("items").Count(context.Background())
+	if err != nil {
+		t.Fatalf("count: %s", err)
+	}
+
+	return n
+}
+
+func TestRetryableTxError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("boom"), false},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, true},
+		{"mysql duplicate", &mysql.MySQLError{Number: 1062}, false},
+		{"mssql deadlock", mssql.Error{Number: 1205}, true},
+		{"mssql other", mssql.Error{Number: 2627}, false},
+		{"wrapped", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1213}), true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := retryableTxError(c.err); got != c.want {
+				t.Errorf("retryableTxError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRunInTxRetry(t *testing.T) {
+	r := testRuntimeDB(t, testConfig(t, `{"database": {"tx_retry": {"attempts": 3, "initial_delay": 1, "max_delay": 1}}}`))
+	ctx := context.Background()
+
+	calls := 0
+	err := r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		calls++
+		_, err := r.IDB(ctx).ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", "a")
+		if err != nil {
+			return err
+		}
+
+		if calls < 3 {
+			return &mysql.MySQLError{Number: 1213}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("fn called %d times, want 3", calls)
+	}
+
+	if n := countItems(t, r.db); n != 1 {
+		t.Errorf("%d rows after retries, want 1", n)
+	}
+
+	calls = 0
+	err = r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		calls++
+
+		return errors.New("boom")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("non-retryable error: err %v after %d calls", err, calls)
+	}
+}
+
+func TestRunInTxNested(t *testing.T) {
+	r := testRuntimeDB(t, &Config{})
+	ctx := context.Background()
+
+	err := r.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := r.IDB(ctx).ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", "outer")
+		if err != nil {
+			return err
+		}
+
+		inner := r.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			_, err := r.IDB(ctx).ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", "inner")
+			if err != nil {
+				return err
+			}
+
+			return errors.New("rollback savepoint")
+		})
+		if inner == nil {
+			t.Error("savepoint error not returned")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	if n := countItems(t, r.db); n != 1 {
+		t.Errorf("%d rows after savepoint rollback, want 1", n)
+	}
+
+	if r.IDB(ctx) != bun.IDB(r.db) {
+		t.Error("IDB outside transaction is not DB")
+	}
+}
+
+func TestRunInTxNamed(t *testing.T) {
+	r := testRuntimeDB(t, &Config{})
+	legacy := testRuntimeDB(t, &Config{})
+	r.dbs = map[string]*database{"legacy": {db: legacy.db}}
+	ctx := context.Background()
+
+	err := r.RunInTxNamed(ctx, "legacy", nil, func(ctx context.Context, tx bun.Tx) error {
+		if r.IDBNamed(ctx, "legacy") != bun.IDB(tx) {
+			t.Error("IDBNamed is not transaction of RunInTxNamed")
+		}
+
+		if r.IDB(ctx) != bun.IDB(r.db) {
+			t.Error("IDB joined transaction of another database")
+		}
+
+		_, err := r.IDBNamed(ctx, "legacy").ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", "legacy")
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("run: %s", err)
+	}
+
+	if n := countItems(t, legacy.db); n != 1 {
+		t.Errorf("%d rows in legacy, want 1", n)
+	}
+
+	if n := countItems(t, r.db); n != 0 {
+		t.Errorf("%d rows in main, want 0", n)
+	}
+
+	err = r.RunInTxNamed(ctx, "missing", nil, func(ctx context.Context, tx bun.Tx) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("transaction on unknown database")
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
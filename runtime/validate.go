@@ -0,0 +1,239 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file validate.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+type validator struct {
+	errs error
+}
+
+func (v *validator) check(ok bool, path, format string, args ...any) {
+	if !ok {
+		v.errs = errors.Join(v.errs, fmt.Errorf("%s.%s: %s", BaseConfigKey, path, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) driver(path string, err error) {
+	if err != nil {
+		v.check(false, path, "%s", err.Error())
+	}
+}
+
+func (v *validator) addresses(path string, addrs []string) {
+	for i, addr := range addrs {
+		v.check(strings.TrimSpace(addr) != "", fmt.Sprintf("%s[%d]", path, i), "empty address")
+	}
+}
+
+func (v *validator) retry(path string, cfg *configRetry) {
+	if cfg == nil {
+		return
+	}
+
+	mode := strings.ToLower(cfg.Mode)
+	v.check(cfg.Attempts >= 0, path+".attempts", "must not be negative")
+	v.check(cfg.InitialDelay >= 0, path+".initial_delay", "must not be negative")
+	v.check(cfg.MaxDelay >= 0, path+".max_delay", "must not be negative")
+	v.check(cfg.MaxDelay == 0 || cfg.MaxDelay >= cfg.InitialDelay, path+".max_delay", "must not be less than initial_delay")
+	v.check(cfg.Jitter >= 0 && cfg.Jitter <= 1, path+".jitter", "must be between 0 and 1")
+	v.check(mode == "" || mode == RetryModeFailFast || mode == RetryModeDegraded, path+".mode", "must be %s or %s", RetryModeFailFast, RetryModeDegraded)
+}
+
+func (v *validator) database(path string, cfg *configDatabase) {
+	v.driver(path+".driver", driverError(databaseDrivers, cfg.Driver))
+	v.check(cfg.DSN != "", path+".dsn", "required")
+	v.check(cfg.SlowQueryDuration >= 0, path+".slow_query_duration", "must not be negative")
+	v.check(cfg.ConnMaxLifetime >= 0, path+".conn_max_lifetime", "must not be negative")
+	v.check(cfg.ConnMaxIdleTime >= 0, path+".conn_max_idle_time", "must not be negative")
+	v.check(cfg.DialTimeout >= 0, path+".dial_timeout", "must not be negative")
+	v.check(cfg.ReadTimeout >= 0, path+".read_timeout", "must not be negative")
+	v.check(cfg.WriteTimeout >= 0, path+".write_timeout", "must not be negative")
+	for i, dsn := range cfg.Replicas {
+		v.check(dsn != "", fmt.Sprintf("%s.replicas[%d]", path, i), "required")
+	}
+
+	policy := strings.ToLower(cfg.ReplicaPolicy)
+	v.check(
+		policy == "" || policy == ReplicaPolicyRoundRobin || policy == ReplicaPolicyLeastConn,
+		path+".replica_policy",
+		"must be %s or %s", ReplicaPolicyRoundRobin, ReplicaPolicyLeastConn,
+	)
+	v.check(cfg.ReplicaMaxLag >= 0, path+".replica_max_lag", "must not be negative")
+	v.check(cfg.ReplicaCheckInterval >= 0, path+".replica_check_interval", "must not be negative")
+	v.retry(path+".retry", cfg.Retry)
+	v.retry(path+".tx_retry", cfg.TxRetry)
+	if cfg.Migrate != nil {
+		v.check(cfg.Migrate.LockTimeout >= 0, path+".migrate.lock_timeout", "must not be negative")
+		v.check(cfg.Migrate.LockTTL >= 0, path+".migrate.lock_ttl", "must not be negative")
+	}
+}
+
+func driverError[F any](s *driverSet[F], name string) error {
+	_, err := s.get(name)
+
+	return err
+}
+
+// Validate checks every section, returns all bad fields in one error
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.Registry != nil {
+		v.driver("registry.driver", driverError(registryDrivers, c.Registry.Driver))
+		v.addresses("registry.address", c.Registry.Address)
+	}
+
+	if c.Broker != nil {
+		v.driver("broker.driver", driverError(brokerDrivers, c.Broker.Driver))
+		v.addresses("broker.address", c.Broker.Address)
+		v.retry("broker.retry", c.Broker.Retry)
+	}
+
+	if c.Cache != nil {
+		v.driver("cache.driver", driverError(cacheDrivers, c.Cache.Driver))
+		v.addresses("cache.address", c.Cache.Address)
+		v.check(c.Cache.DB >= 0, "cache.db", "must not be negative")
+	}
+
+	if c.Store != nil {
+		v.driver("store.driver", driverError(storeDrivers, c.Store.Driver))
+		v.addresses("store.address", c.Store.Address)
+		v.check(c.Store.DB >= 0, "store.db", "must not be negative")
+	}
+
+	if c.Database != nil {
+		v.database("database", c.Database)
+	}
+
+	names := make([]string, 0, len(c.Databases))
+	for name := range c.Databases {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		db := c.Databases[name]
+		if db == nil {
+			v.check(false, "databases."+name, "empty section")
+
+			continue
+		}
+
+		v.database("databases."+name, db)
+	}
+
+	// DB() and DBNamed would return different connections
+	_, named := c.Databases[DefaultDatabaseName]
+	v.check(c.Database == nil || !named, "databases."+DefaultDatabaseName, "conflicts with database section")
+
+	if c.Mongo != nil {
+		v.check(c.Mongo.DSN != "", "mongo.dsn", "required")
+		v.check(c.Mongo.SlowCommandDuration >= 0, "mongo.slow_command_duration", "must not be negative")
+		v.check(c.Mongo.MaxPoolSize >= 0, "mongo.max_pool_size", "must not be negative")
+		v.check(c.Mongo.MinPoolSize >= 0, "mongo.min_pool_size", "must not be negative")
+		v.check(c.Mongo.MaxPoolSize == 0 || c.Mongo.MinPoolSize <= c.Mongo.MaxPoolSize, "mongo.min_pool_size", "must not be greater than max_pool_size")
+		v.check(c.Mongo.ConnectTimeout >= 0, "mongo.connect_timeout", "must not be negative")
+		v.check(c.Mongo.ServerSelectionTimeout >= 0, "mongo.server_selection_timeout", "must not be negative")
+		if c.Mongo.ReadPreference != "" {
+			_, err := mongoReadPref(c.Mongo.ReadPreference)
+			v.check(err == nil, "mongo.read_preference", "unknown mode <%s>", c.Mongo.ReadPreference)
+		}
+
+		if c.Mongo.WriteConcern != "" {
+			_, err := mongoWriteConcern(c.Mongo.WriteConcern)
+			v.check(err == nil, "mongo.write_concern", "must be majority or a number")
+		}
+
+		v.retry("mongo.retry", c.Mongo.Retry)
+		v.retry("mongo.tx_retry", c.Mongo.TxRetry)
+		if c.Mongo.Migrate != nil {
+			v.check(c.Mongo.Migrate.LockTimeout >= 0, "mongo.migrate.lock_timeout", "must not be negative")
+			v.check(c.Mongo.Migrate.LockTTL >= 0, "mongo.migrate.lock_ttl", "must not be negative")
+		}
+	}
+
+	if c.Redis != nil {
+		v.check(len(c.Redis.Address) > 0, "redis.address", "required")
+		v.addresses("redis.address", c.Redis.Address)
+		v.check(c.Redis.MasterName != "" || len(c.Redis.Address) < 2 || c.Redis.DB == 0, "redis.db", "cluster supports db 0 only")
+		v.check(c.Redis.PoolSize >= 0, "redis.pool_size", "must not be negative")
+		v.check(c.Redis.MaxRetries >= -1, "redis.max_retries", "must be -1 (disabled) or greater")
+		v.check(c.Redis.DB >= 0, "redis.db", "must not be negative")
+		v.retry("redis.retry", c.Redis.Retry)
+	}
+
+	if c.Fiber != nil {
+		if c.Fiber.Address != "" {
+			_, _, err := net.SplitHostPort(c.Fiber.Address)
+			v.check(err == nil, "fiber.address", "invalid listen address <%s>", c.Fiber.Address)
+		}
+
+		v.check(c.Fiber.BodyLimit >= 0, "fiber.body_limit", "must not be negative")
+		v.check(c.Fiber.Concurrency >= 0, "fiber.concurrency", "must not be negative")
+		v.check(c.Fiber.ReadBufferSize >= 0, "fiber.read_buffer_size", "must not be negative")
+		v.check(c.Fiber.WriteBufferSize >= 0, "fiber.write_buffer_size", "must not be negative")
+		v.check(c.Fiber.HealthCheckTimeout >= 0, "fiber.health_check_timeout", "must not be negative")
+		if c.Fiber.Cors != nil {
+			origins := strings.TrimSpace(c.Fiber.Cors.AllowOrigins)
+			v.check(
+				!c.Fiber.Cors.AllowCredentials || (origins != "" && origins != "*"),
+				"fiber.cors.allow_credentials",
+				"not allowed with wildcard origins",
+			)
+			v.check(c.Fiber.Cors.MaxAge >= 0, "fiber.cors.max_age", "must not be negative")
+		}
+
+		if c.Fiber.QueryStats != nil {
+			v.check(c.Fiber.QueryStats.MaxQueries >= 0, "fiber.query_stats.max_queries", "must not be negative")
+			v.check(c.Fiber.QueryStats.MaxRepeats >= 0, "fiber.query_stats.max_repeats", "must not be negative")
+		}
+	}
+
+	if c.Session != nil {
+		v.check(c.Session.Expiration >= 0, "session.expiration", "must not be negative")
+	}
+
+	if c.Tenant != nil {
+		src := strings.ToLower(c.Tenant.Source)
+		v.check(src == "" || src == "session" || src == "header", "tenant.source", "must be session or header")
+	}
+
+	if c.Audit != nil {
+		sink := strings.ToLower(c.Audit.Sink)
+		v.check(sink == "" || sink == AuditSinkDatabase || sink == AuditSinkBroker, "audit.sink", "must be %s or %s", AuditSinkDatabase, AuditSinkBroker)
+		v.check(sink != AuditSinkBroker || c.Broker != nil, "audit.sink", "requires broker section")
+	}
+
+	// Missing dependencies between sections
+	_, err := componentOrder(c)
+	v.errs = errors.Join(v.errs, err)
+
+	return v.errs
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
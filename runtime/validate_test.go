@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file validate_test.go
+ * @package runtime
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		// Substrings of error, none for valid config
+		want []string
+	}{
+		{
+			name: "empty",
+			src:  `{}`,
+		},
+		{
+			name: "valid",
+			src: `{
+				"database": {"driver": "sqlite", "dsn": "file:x", "replica_policy": "least_conn", "retry": {"mode": "degraded", "jitter": 0.5}},
+				"redis": {"address": ["a:6379"]},
+				"fiber": {"address": ":8080", "cors": {"allow_origins": "https://a.com", "allow_credentials": true}},
+				"session": {"id_source": "header"},
+				"tenant": {"source": "session"}
+			}`,
+		},
+		{
+			name: "database",
+			src: `{"database": {"driver": "oracle", "max_open_conns": 1, "slow_query_duration": -1, "replica_policy": "random",
+				"retry": {"attempts": -1, "initial_delay": 100, "max_delay": 10, "jitter": 2, "mode": "later"},
+				"migrate": {"lock_timeout": -1, "lock_ttl": -1}}}`,
+			want: []string{
+				"base.database.driver:",
+				"base.database.dsn: required",
+				"base.database.slow_query_duration: must not be negative",
+				"base.database.replica_policy: must be round_robin or least_conn",
+				"base.database.retry.attempts: must not be negative",
+				"base.database.retry.max_delay: must not be less than initial_delay",
+				"base.database.retry.jitter: must be between 0 and 1",
+				"base.database.retry.mode: must be fail_fast or degraded",
+				"base.database.migrate.lock_timeout: must not be negative",
+				"base.database.migrate.lock_ttl: must not be negative",
+			},
+		},
+		{
+			name: "database and main",
+			src:  `{"database": {"driver": "sqlite", "dsn": "file:a"}, "databases": {"main": {"driver": "sqlite", "dsn": "file:b"}}}`,
+			want: []string{"base.databases.main: conflicts with database section"},
+		},
+		{
+			name: "named databases",
+			src:  `{"databases": {"b": null, "a": {"driver": "sqlite"}}}`,
+			want: []string{"base.databases.a.dsn: required", "base.databases.b: empty section"},
+		},
+		{
+			name: "fiber",
+			src:  `{"fiber": {"address": "8080", "body_limit": -1, "cors": {"allow_origins": "*", "allow_credentials": true}}}`,
+			want: []string{
+				"base.fiber.address: invalid listen address <8080>",
+				"base.fiber.body_limit: must not be negative",
+				"base.fiber.cors.allow_credentials: not allowed with wildcard origins",
+			},
+		},
+		{
+			name: "mongo",
+			src:  `{"mongo": {"min_pool_size": 5, "max_pool_size": 2, "read_preference": "any", "write_concern": "all", "migrate": {"lock_ttl": -1}}}`,
+			want: []string{
+				"base.mongo.dsn: required",
+				"base.mongo.min_pool_size: must not be greater than max_pool_size",
+				"base.mongo.read_preference: unknown mode <any>",
+				"base.mongo.write_concern: must be majority or a number",
+				"base.mongo.migrate.lock_ttl: must not be negative",
+			},
+		},
+		{
+			name: "redis",
+			src:  `{"redis": {"address": ["a:1", " "], "db": 1, "max_retries": -2}}`,
+			want: []string{
+				"base.redis.address[1]: empty address",
+				"base.redis.db: cluster supports db 0 only",
+				"base.redis.max_retries: must be -1 (disabled) or greater",
+			},
+		},
+		{
+			name: "sections",
+			src:  `{"session": {"id_source": "query"}, "tenant": {"source": "jwt"}, "audit": {"sink": "broker"}}`,
+			want: []string{
+				"base.tenant.source: must be session or header",
+				"base.audit.sink: requires broker section",
+				"base.session: requires fiber section",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testConfig(t, tt.src).Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatal("want error")
+			}
+
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q does not contain %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -32,6 +32,14 @@ const (
 	CodeStorageFailed   = 9999500001
 	MsgStorageFailed    = "Storage failed"
 
+	CodeHealthCheckFailed = 9999503001
+	MsgHealthCheckFailed  = "Health check failed"
+
+	CodeTenantRequired = 9999403001
+	MsgTenantRequired  = "Tenant required"
+	CodeTenantDenied   = 9999403002
+	MsgTenantDenied    = "Tenant denied"
+
 	CodeGeneralFailed = 9999999999
 	MsgGeneralFailed  = "General failed"
 )
@@ -43,6 +51,8 @@ type Envelope struct {
 	Message   string      `json:"message"`
 	RequestId string      `json:"request_id,omitempty"`
 	Data      interface{} `json:"data,omitempty"`
+
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 func WrapResponse(data interface{}) *Envelope {
@@ -86,6 +96,17 @@ func (e *Envelope) SetRequestId(id string) *Envelope {
 	return e
 }
 
+func (e *Envelope) SetPagination(p *Pagination) *Envelope {
+	e.Pagination = p
+
+	return e
+}
+
+// WrapPage wraps one page of items with its pagination block
+func WrapPage(items interface{}, p *Pagination) *Envelope {
+	return WrapResponse(items).SetPagination(p)
+}
+
 /*
  * Local variables:
  * tab-width: 4
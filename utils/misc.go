@@ -17,6 +17,8 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"math/rand"
+
+	"github.com/google/uuid"
 )
 
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -60,6 +62,22 @@ func EnsureStatus(status, bit int64) bool {
 	return (status & (1 << bit)) != 0
 }
 
+// ParseUUID reads UUID from session or header values, false if empty or
+// invalid
+func ParseUUID(v any) (uuid.UUID, bool) {
+	var id uuid.UUID
+	switch tv := v.(type) {
+	case uuid.UUID:
+		id = tv
+	case string:
+		id, _ = uuid.Parse(tv)
+	case []byte:
+		id, _ = uuid.ParseBytes(tv)
+	}
+
+	return id, id != uuid.Nil
+}
+
 /*
  * Local variables:
  * tab-width: 4
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file pagination.go
+ * @package utils
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/defs"
+)
+
+type PageRequest struct {
+	Page   int
+	Size   int
+	Cursor string
+}
+
+// Pagination is the pagination block of Envelope, Page and Total are set in
+// offset mode, NextCursor in keyset mode
+type Pagination struct {
+	Page       int    `json:"page,omitempty"`
+	Size       int    `json:"size"`
+	Total      *int   `json:"total,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ParsePage reads page, size and cursor query parameters, size defaults to
+// defs.DefaultPageSize and is limited to defs.MaxPageSize
+func ParsePage(c *fiber.Ctx) (*PageRequest, error) {
+	p := &PageRequest{
+		Page:   1,
+		Size:   defs.DefaultPageSize,
+		Cursor: c.Query("cursor"),
+	}
+
+	if v := c.Query("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, fmt.Errorf("invalid page <%s>", v)
+		}
+
+		p.Page = page
+	}
+
+	if v := c.Query("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid size <%s>", v)
+		}
+
+		p.Size = min(size, defs.MaxPageSize)
+	}
+
+	return p, nil
+}
+
+// Paginate applies offset pagination to q and counts total. Count runs after
+// Scan on the same query, so it keeps filters added by select hooks.
+func Paginate[T any](ctx context.Context, q *bun.SelectQuery, p *PageRequest) ([]T, *Pagination, error) {
+	var items []T
+	err := q.Model(&items).Limit(p.Size).Offset((p.Page - 1) * p.Size).Scan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Total is known without count on a partial first page
+	total := len(items)
+	if p.Page > 1 || total == p.Size {
+		total, err = q.Count(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return items, &Pagination{
+		Page:    p.Page,
+		Size:    p.Size,
+		Total:   &total,
+		HasMore: p.Page*p.Size < total,
+	}, nil
+}
+
+// PaginateKeyset applies keyset pagination on column of T to q, which should
+// be unique and is the only order of result. Cursor is the value of column
+// in the last item.
+func PaginateKeyset[T any](ctx context.Context, q *bun.SelectQuery, p *PageRequest, column string, desc bool) ([]T, *Pagination, error) {
+	table := q.DB().Table(reflect.TypeOf((*T)(nil)).Elem())
+	field, ok := table.FieldMap[column]
+	if !ok {
+		return nil, nil, fmt.Errorf("keyset column <%s> not found in %s", column, table.TypeName)
+	}
+
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+
+	if p.Cursor != "" {
+		value := reflect.New(field.StructField.Type)
+		src, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+		if err == nil {
+			err = json.Unmarshal(src, value.Interface())
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cursor <%s>", p.Cursor)
+		}
+
+		q.Where("?TableAlias.? "+op+" ?", bun.Ident(field.Name), value.Elem().Interface())
+	}
+
+	// One more item tells if there is next page
+	var items []T
+	err := q.Model(&items).OrderExpr("?TableAlias.? "+order, bun.Ident(field.Name)).Limit(p.Size + 1).Scan(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pg := &Pagination{
+		Size: p.Size,
+	}
+	if len(items) > p.Size {
+		items = items[:p.Size]
+		pg.HasMore = true
+
+		last := field.Value(reflect.ValueOf(&items[len(items)-1]).Elem())
+		src, err := json.Marshal(last.Interface())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pg.NextCursor = base64.RawURLEncoding.EncodeToString(src)
+	}
+
+	return items, pg, nil
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
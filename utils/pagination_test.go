@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file pagination_test.go
+ * @package utils
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package utils
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/uptrace/bun"
+	"github.com/zenkoo-live/svc.base/defs"
+	"github.com/zenkoo-live/svc.base/internal/testdb"
+)
+
+type testItem struct {
+	bun.BaseModel `bun:"table:items"`
+
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+func testItems(t *testing.T, n int) *bun.DB {
+	t.Helper()
+
+	db := testdb.SQLite(t)
+	ctx := context.Background()
+	_, err := db.NewCreateTable().Model((*testItem)(nil)).Exec(ctx)
+	if err != nil {
+		t.Fatalf("create table: %s", err)
+	}
+
+	for i := 0; i < n; i++ {
+		_, err = db.NewInsert().Model(&testItem{Name: "item"}).Exec(ctx)
+		if err != nil {
+			t.Fatalf("insert: %s", err)
+		}
+	}
+
+	return db
+}
+
+func TestParsePage(t *testing.T) {
+	cases := []struct {
+		query string
+		want  PageRequest
+		err   bool
+	}{
+		{"", PageRequest{Page: 1, Size: defs.DefaultPageSize}, false},
+		{"?page=3&size=5&cursor=abc", PageRequest{Page: 3, Size: 5, Cursor: "abc"}, false},
+		{"?size=100000", PageRequest{Page: 1, Size: defs.MaxPageSize}, false},
+		{"?page=0", PageRequest{}, true},
+		{"?size=-1", PageRequest{}, true},
+		{"?page=x", PageRequest{}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			var (
+				got *PageRequest
+				err error
+			)
+
+			app := fiber.New()
+			app.Get("/", func(ctx *fiber.Ctx) error {
+				got, err = ParsePage(ctx)
+
+				return nil
+			})
+
+			_, terr := app.Test(httptest.NewRequest("GET", "/"+c.query, nil))
+			if terr != nil {
+				t.Fatal(terr)
+			}
+
+			if c.err {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parse: %s", err)
+			}
+
+			if *got != c.want {
+				t.Errorf("parsed %+v, want %+v", *got, c.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	db := testItems(t, 7)
+	ctx := context.Background()
+
+	cases := []struct {
+		page, size int
+		items      int
+		more       bool
+	}{
+		{1, 3, 3, true},
+		{3, 3, 1, false},
+		{1, 10, 7, false},
+		{4, 3, 0, false},
+	}
+
+	for _, c := range cases {
+		items, pg, err := Paginate[testItem](ctx, db.NewSelect().Order("id"), &PageRequest{Page: c.page, Size: c.size})
+		if err != nil {
+			t.Fatalf("page %d/%d: %s", c.page, c.size, err)
+		}
+
+		if len(items) != c.items || pg.HasMore != c.more || pg.Total == nil || *pg.Total != 7 {
+			t.Errorf("page %d/%d: %d items, more %v, total %v", c.page, c.size, len(items), pg.HasMore, pg.Total)
+		}
+	}
+}
+
+func TestPaginateKeyset(t *testing.T) {
+	db := testItems(t, 7)
+	ctx := context.Background()
+
+	for _, desc := range []bool{false, true} {
+		var (
+			seen []int64
+			p    = &PageRequest{Size: 3}
+		)
+
+		for {
+			items, pg, err := PaginateKeyset[testItem](ctx, db.NewSelect(), p, "id", desc)
+			if err != nil {
+				t.Fatalf("keyset: %s", err)
+			}
+
+			for _, item := range items {
+				seen = append(seen, item.ID)
+			}
+
+			if !pg.HasMore {
+				break
+			}
+
+			p.Cursor = pg.NextCursor
+		}
+
+		if len(seen) != 7 {
+			t.Fatalf("desc %v: walked %v", desc, seen)
+		}
+
+		for i := 1; i < len(seen); i++ {
+			if (seen[i] > seen[i-1]) == desc {
+				t.Errorf("desc %v: out of order %v", desc, seen)
+
+				break
+			}
+		}
+	}
+
+	_, _, err := PaginateKeyset[testItem](ctx, db.NewSelect(), &PageRequest{Size: 3, Cursor: "!!"}, "id", false)
+	if err == nil {
+		t.Error("invalid cursor accepted")
+	}
+
+	_, _, err = PaginateKeyset[testItem](ctx, db.NewSelect(), &PageRequest{Size: 3}, "missing", false)
+	if err == nil {
+		t.Error("unknown column accepted")
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
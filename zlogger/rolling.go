@@ -74,6 +74,7 @@ type RollingFile struct {
 
 	closed    bool
 	exit      chan struct{}
+	done      chan struct{}
 	syncFlush chan struct{}
 
 	file       *os.File
@@ -267,6 +268,7 @@ func (r *RollingFile) Close() error {
 	r.closed = true
 	r.mu.Unlock()
 	close(r.exit)
+	<-r.done
 
 	return nil
 }
@@ -348,11 +350,16 @@ func (r *RollingFile) flushRoutine() {
 
 	//FIXME better solution ?
 	defer func() {
+		// Writers may still hold current
+		r.mu.Lock()
 		flush()
+		r.mu.Unlock()
 		if f := r.file; f != nil {
 			r.file = nil
 			f.Close()
 		}
+
+		close(r.done)
 	}()
 	for {
 		select {
@@ -398,6 +405,7 @@ func NewRollingFile(basePath string, rolling RollingFormat) (*RollingFile, error
 		basePath:   basePath,
 		rolling:    rolling,
 		exit:       make(chan struct{}),
+		done:       make(chan struct{}),
 		syncFlush:  make(chan struct{}),
 		closed:     false,
 		fullBuffer: make(chan *bytes.Buffer, logPageNumber+1),
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) Zenkoo, Inc - All Rights Reserved
+ * Unauthorized copying of this file, via any medium is strictly prohibited
+ * Proprietary and confidential
+ */
+
+/**
+ * @file rolling_test.go
+ * @package zlogger
+ * @author Dr.NP <conan.np@gmail.com>
+ * @since 10/17/2026
+ */
+
+package zlogger
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRollingFileClose(t *testing.T) {
+	r, err := NewRollingFile(filepath.Join(t.TempDir(), "test"), DailyRolling)
+	if err != nil {
+		t.Fatalf("new rolling file: %s", err)
+	}
+
+	r.Write([]byte("line\n"))
+
+	// Writers still running while Close flushes, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := r.Write([]byte("line\n")); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	r.Close()
+	wg.Wait()
+
+	if _, err := r.Write([]byte("late\n")); err != ErrClosedRollingFile {
+		t.Errorf("write after close: %v", err)
+	}
+
+	// Daily rolling puts files in a month directory
+	var b []byte
+	filepath.WalkDir(filepath.Dir(r.basePath), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			data, _ := os.ReadFile(path)
+			b = append(b, data...)
+		}
+
+		return nil
+	})
+
+	if n := strings.Count(string(b), "line\n"); n == 0 || n > 401 {
+		t.Errorf("%d lines flushed", n)
+	}
+}
+
+/*
+ * Local variables:
+ * tab-width: 4
+ * c-basic-offset: 4
+ * End:
+ * vim600: sw=4 ts=4 fdm=marker
+ * vim<600: sw=4 ts=4
+ */
@@ -15,9 +15,11 @@ package zlogger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"go-micro.dev/v4/logger"
 	"go.uber.org/zap"
@@ -29,7 +31,13 @@ type Zaplog struct {
 	zap  *zap.Logger
 	opts logger.Options
 	sync.RWMutex
-	fields map[string]interface{}
+	fields   map[string]interface{}
+	rollings []*RollingFile
+
+	// Switches shared with derived loggers, changeable at runtime
+	level *atomic.Int32
+	echo  *atomic.Bool
+	disk  *atomic.Bool
 }
 
 const (
@@ -93,33 +101,54 @@ func (l *Zaplog) Init(opts ...logger.Option) error {
 		disk = true
 	}
 
+	l.level = &atomic.Int32{}
+	l.level.Store(int32(loggerToZapLevel(l.opts.Level)))
+	l.echo = &atomic.Bool{}
+	l.echo.Store(echo)
+	l.disk = &atomic.Bool{}
+	l.disk.Store(disk)
+
 	// Levels
-	showLevel := loggerToZapLevel(l.opts.Level)
+	showLevel := func() zapcore.Level {
+		return zapcore.Level(l.level.Load())
+	}
 	// TRACE
 	lvlTrace := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= TraceLevel && lvl == TraceLevel
+		return showLevel() <= TraceLevel && lvl == TraceLevel
 	})
 	// DEBUG
 	lvlDebug := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= zapcore.DebugLevel && lvl == zapcore.DebugLevel
+		return showLevel() <= zapcore.DebugLevel && lvl == zapcore.DebugLevel
 	})
 	// INFO
 	lvlInfo := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= zapcore.InfoLevel && lvl == zapcore.InfoLevel
+		return showLevel() <= zapcore.InfoLevel && lvl == zapcore.InfoLevel
 	})
 	// WARN
 	lvlWarn := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= zapcore.WarnLevel && lvl == zapcore.WarnLevel
+		return showLevel() <= zapcore.WarnLevel && lvl == zapcore.WarnLevel
 	})
 	// ERROR
 	lvlError := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= zapcore.ErrorLevel && lvl == zapcore.ErrorLevel
+		return showLevel() <= zapcore.ErrorLevel && lvl == zapcore.ErrorLevel
 	})
 	// FATAL
 	lvlFatal := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-		return showLevel <= zapcore.FatalLevel && lvl >= zapcore.FatalLevel
+		return showLevel() <= zapcore.FatalLevel && lvl >= zapcore.FatalLevel
 	})
 
+	// Output switches
+	onDisk := func(enabler zap.LevelEnablerFunc) zap.LevelEnablerFunc {
+		return func(lvl zapcore.Level) bool {
+			return l.disk.Load() && enabler(lvl)
+		}
+	}
+	onEcho := func(enabler zap.LevelEnablerFunc) zap.LevelEnablerFunc {
+		return func(lvl zapcore.Level) bool {
+			return l.echo.Load() && enabler(lvl)
+		}
+	}
+
 	// Syncers
 	syncerStdout := zapcore.AddSync(os.Stdout)
 	syncerStderr := zapcore.AddSync(os.Stderr)
@@ -166,30 +195,26 @@ func (l *Zaplog) Init(opts ...logger.Option) error {
 	// Cores
 	jsonEncoder := zapcore.NewJSONEncoder(_defaultEncoderConfig)
 	consoleEncoder := zapcore.NewJSONEncoder(_defaultEncoderConfig)
-	if disk {
-		cores = append(
-			cores,
-			zapcore.NewCore(jsonEncoder, syncerRollingTrace, lvlTrace),
-			zapcore.NewCore(jsonEncoder, syncerRollingDebug, lvlDebug),
-			zapcore.NewCore(jsonEncoder, syncerRollingInfo, lvlInfo),
-			zapcore.NewCore(jsonEncoder, syncerRollingWarn, lvlWarn),
-			zapcore.NewCore(jsonEncoder, syncerRollingError, lvlError),
-			zapcore.NewCore(jsonEncoder, syncerRollingFatal, lvlFatal),
-			// Must say
-			zapcore.NewCore(consoleEncoder, syncerStderr, lvlFatal),
-		)
-	}
-
-	if echo {
-		cores = append(
-			cores,
-			zapcore.NewCore(consoleEncoder, syncerStdout, lvlTrace),
-			zapcore.NewCore(consoleEncoder, syncerStdout, lvlDebug),
-			zapcore.NewCore(consoleEncoder, syncerStdout, lvlInfo),
-			zapcore.NewCore(consoleEncoder, syncerStdout, lvlWarn),
-			zapcore.NewCore(consoleEncoder, syncerStderr, lvlError),
-		)
-	}
+	cores = append(
+		cores,
+		zapcore.NewCore(jsonEncoder, syncerRollingTrace, onDisk(lvlTrace)),
+		zapcore.NewCore(jsonEncoder, syncerRollingDebug, onDisk(lvlDebug)),
+		zapcore.NewCore(jsonEncoder, syncerRollingInfo, onDisk(lvlInfo)),
+		zapcore.NewCore(jsonEncoder, syncerRollingWarn, onDisk(lvlWarn)),
+		zapcore.NewCore(jsonEncoder, syncerRollingError, onDisk(lvlError)),
+		zapcore.NewCore(jsonEncoder, syncerRollingFatal, onDisk(lvlFatal)),
+		// Must say
+		zapcore.NewCore(consoleEncoder, syncerStderr, onDisk(lvlFatal)),
+	)
+
+	cores = append(
+		cores,
+		zapcore.NewCore(consoleEncoder, syncerStdout, onEcho(lvlTrace)),
+		zapcore.NewCore(consoleEncoder, syncerStdout, onEcho(lvlDebug)),
+		zapcore.NewCore(consoleEncoder, syncerStdout, onEcho(lvlInfo)),
+		zapcore.NewCore(consoleEncoder, syncerStdout, onEcho(lvlWarn)),
+		zapcore.NewCore(consoleEncoder, syncerStderr, onEcho(lvlError)),
+	)
 
 	// NewTee
 	core := zapcore.NewTee(cores...)
@@ -214,6 +239,14 @@ func (l *Zaplog) Init(opts ...logger.Option) error {
 	//l.cfg = zapConfig
 	l.zap = log
 	l.fields = make(map[string]interface{})
+	l.rollings = []*RollingFile{
+		rollingTrace,
+		rollingDebug,
+		rollingInfo,
+		rollingWarn,
+		rollingError,
+		rollingFatal,
+	}
 
 	return nil
 }
@@ -237,8 +270,11 @@ func (l *Zaplog) Fields(fields map[string]interface{}) logger.Logger {
 	zl := &Zaplog{
 		cfg:    l.cfg,
 		zap:    l.zap.With(data...),
-		opts:   l.opts,
+		opts:   l.Options(),
 		fields: make(map[string]interface{}),
+		level:  l.level,
+		echo:   l.echo,
+		disk:   l.disk,
 	}
 
 	return zl
@@ -315,6 +351,9 @@ func (l *Zaplog) String() string {
 }
 
 func (l *Zaplog) Options() logger.Options {
+	l.RLock()
+	defer l.RUnlock()
+
 	return l.opts
 }
 
@@ -326,6 +365,34 @@ func (l *Zaplog) Level() logger.Level {
 	return zapToLoggerLevel(l.zap.Level())
 }
 
+// SetLevel changes output level of logger and all derived loggers
+func (l *Zaplog) SetLevel(level logger.Level) {
+	l.Lock()
+	l.opts.Level = level
+	l.Unlock()
+	l.level.Store(int32(loggerToZapLevel(level)))
+}
+
+// SetEcho switches stdout output
+func (l *Zaplog) SetEcho(echo bool) {
+	l.echo.Store(echo)
+}
+
+// SetDisk switches rolling files output
+func (l *Zaplog) SetDisk(disk bool) {
+	l.disk.Store(disk)
+}
+
+// Close flushes buffered entries and closes rolling files
+func (l *Zaplog) Close() error {
+	var errs error
+	for _, r := range l.rollings {
+		errs = errors.Join(errs, r.Close())
+	}
+
+	return errs
+}
+
 // NewLogger builds a new logger based on options
 func NewLogger(opts ...logger.Option) (*Zaplog, error) {
 	// Default options